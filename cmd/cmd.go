@@ -10,7 +10,10 @@ import (
 	"os"
 )
 
-var helpFlag = flag.Bool("help", false, "Prints help message")
+var (
+	helpFlag = flag.Bool("help", false, "Prints help message")
+	userFlag = flag.String("user", "", "User whose subscriptions the command acts on, RSSHUB_USER by default")
+)
 
 func Run() {
 	flag.Parse()
@@ -30,6 +33,9 @@ func Run() {
 		logger.Error(ctx, "failed to init config", "error", err)
 		os.Exit(1)
 	}
+	if *userFlag != "" {
+		cfg.CLI.User = *userFlag
+	}
 
 	// Creating application
 	application, err := app.New(ctx, cfg, logger)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"RSSHub/internal/adapters/cli"
+	"RSSHub/internal/adapters/rest"
 	"RSSHub/pkg/envzilla"
 	"RSSHub/pkg/postgres"
 	"fmt"
@@ -9,6 +11,8 @@ import (
 type (
 	Config struct {
 		Postgres postgres.Config
+		HTTP     rest.Config
+		CLI      cli.Config
 	}
 )
 
@@ -1,30 +1,47 @@
 package cli
 
 import (
+	"RSSHub/internal/adapters/rest"
 	"RSSHub/internal/domain/ports"
 	"RSSHub/pkg/logger"
+	"RSSHub/pkg/migrator"
 	"RSSHub/pkg/utils"
 	"context"
+	"flag"
 	"fmt"
-	"os"
 )
 
+type Config struct {
+	User string `env:"RSSHUB_USER" default:"default"` // Commands act on subscriptions of the user, --user overrides it
+}
+
 type CLIHandler struct {
 	aggregator ports.Aggregator
+	migrator   *migrator.Migrator
+	server     *rest.Server
+	user       string
 	args       []string
 
 	log logger.Logger
 }
 
-func NewCLIHandler(aggregator ports.Aggregator, log logger.Logger) *CLIHandler {
+func NewCLIHandler(cfg Config, aggregator ports.Aggregator, migrator *migrator.Migrator, server *rest.Server, log logger.Logger) *CLIHandler {
 	return &CLIHandler{
 		aggregator: aggregator,
-		args:       os.Args[1:],
+		migrator:   migrator,
+		server:     server,
+		user:       cfg.User,
+		args:       flag.Args(),
 
 		log: log,
 	}
 }
 
+// IsMigrateCommand reports whether the command manages the database schema by hand
+func (h *CLIHandler) IsMigrateCommand() bool {
+	return len(h.args) > 0 && h.args[0] == migrateFlag
+}
+
 func (h *CLIHandler) ParseFlags() error {
 	if len(h.args) < 1 {
 		utils.PrintHelp()
@@ -41,14 +58,42 @@ func (h *CLIHandler) ParseFlags() error {
 		err = h.handleInterval()
 	case setWorkerFlag:
 		err = h.handleWorkers()
+	case setHostLimitFlag:
+		err = h.handleHostLimit()
 	case listFlag:
 		err = h.handleList()
 	case deleteFlag:
 		err = h.handleDelete()
+	case enableFlag:
+		err = h.handleEnable()
 	case articlesFlag:
 		err = h.handleArticle()
+	case markReadFlag:
+		err = h.handleMarkRead()
+	case starFlag, unstarFlag:
+		err = h.handleStar()
+	case searchFlag:
+		err = h.handleSearch()
+	case jobsFlag:
+		err = h.handleJobs()
 	case statusFlag:
 		err = h.handleStatus()
+	case migrateFlag:
+		err = h.handleMigrate()
+	case serveFlag:
+		err = h.handleServe()
+	case importFlag:
+		err = h.handleImport()
+	case exportFlag:
+		err = h.handleExport()
+	case groupFlag:
+		err = h.handleGroup()
+	case rulesFlag:
+		err = h.handleRules()
+	case alertsFlag:
+		err = h.handleAlerts()
+	case userFlag:
+		err = h.handleUser()
 	default:
 		utils.PrintHelp()
 		return fmt.Errorf("flag is undefined: %v", h.args[0])
@@ -4,10 +4,13 @@ import "errors"
 
 var (
 	ErrInvFetchFlag           = errors.New("fetch flag is invalid")
+	ErrInvServeFlag           = errors.New("serve flag is invalid")
 	ErrInvAddFlag             = errors.New("add flag is invalid")
 	ErrInvIntervalFlag        = errors.New("set-interval flag is invalid")
 	ErrInvWorkersFlag         = errors.New("set-workers count flag is invalid")
+	ErrInvHostLimitFlag       = errors.New("set-host-limit flag is invalid")
 	ErrInvDeleteFlag          = errors.New("delete flag is invalid")
+	ErrInvEnableFlag          = errors.New("enable flag is invalid")
 	ErrInvListFlag            = errors.New("list flag is invalid")
 	ErrInvArticlesFlag        = errors.New("articles flag is invalid")
 	ErrInvNumFlag             = errors.New("num must be greater than 0")
@@ -20,8 +23,25 @@ var (
 	ErrEmptyName              = errors.New("--name flag is required")
 	ErrEmptyDesc              = errors.New("--desc flag is required")
 	ErrEmptyUrl               = errors.New("--url flag is required")
+	ErrEmptyCategory          = errors.New("--category value is required")
+	ErrEmptyGroup             = errors.New("--group value is required")
+	ErrInvSinceFlag           = errors.New("--since must be a positive duration, e.g. 7d or 12h")
+	ErrInvBeforeFlag          = errors.New("--before must be a date, e.g. 2006-01-02 or \"2006-01-02 15:04:05\"")
+	ErrInvJobStatus           = errors.New("--status must be one of pending, running, done, dead")
+	ErrInvDeliveryStatus      = errors.New("--status must be one of pending, running, delivered, failed")
 
-	ErrArticleFlagExpected = errors.New("invalid articles command usage, expected \"rsshub articles --feed-name <feed name> --num <num>\"")
+	ErrMarkReadFlagExpected = errors.New("invalid mark-read command usage, expected \"rsshub mark-read --feed-name <feed name> [--before <date>]\"")
+	ErrStarFlagExpected     = errors.New("invalid star command usage, expected \"rsshub star|unstar <article id>\"")
+	ErrSearchFlagExpected   = errors.New("invalid search command usage, expected \"rsshub search <query> [--feed-name <feed name>] [--since <7d|12h>] [--num <num>]\"")
+	ErrImportFlagExpected   = errors.New("invalid import command usage, expected \"rsshub import --opml <file>\"")
+	ErrExportFlagExpected   = errors.New("invalid export command usage, expected \"rsshub export --opml [file]\"")
+	ErrGroupFlagExpected    = errors.New("invalid group command usage, expected \"rsshub group create|delete <group>|add|remove <group> <feed name>|list\"")
+	ErrRulesFlagExpected    = errors.New("invalid rules command usage, expected \"rsshub rules add --field <title|description|author|category> --match <contains|regex> --pattern <pattern> --action <drop|tag|star|mark-read> [--tag <tag>] [--feed-name <feed name>|--group <group>]\" or \"rsshub rules list|delete|test [rule id]\"")
+	ErrAlertsFlagExpected   = errors.New("invalid alerts command usage, expected \"rsshub alerts add --pattern <pattern> [--match <keyword|regex>] --webhook <url> [--feed-name <feed name>]\" or \"rsshub alerts list|delete <alert id>|deliveries [--status <pending|running|delivered|failed>] [--num <num>]\"")
+	ErrUserFlagExpected     = errors.New("invalid user command usage, expected \"rsshub user add|token|grant|revoke|delete <name>|list\"")
+	ErrMigrateFlagExpected  = errors.New("invalid migrate command usage, expected \"rsshub migrate up|down [steps]|status\"")
+	ErrJobsFlagExpected     = errors.New("invalid jobs command usage, expected \"rsshub jobs [--status <pending|running|done|dead>] [--num <num>]\"")
+	ErrArticleFlagExpected  = errors.New("invalid articles command usage, expected \"rsshub articles --feed-name <feed name>|--group <group> [--num <num>] [--category <category>] [--with-media] [--unread] [--starred]\"")
 )
 
 var (
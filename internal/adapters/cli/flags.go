@@ -1,20 +1,95 @@
 package cli
 
 var (
-	fetchFlag       = "fetch"
-	addFlag         = "add"
-	setIntervalFlag = "set-interval"
-	setWorkerFlag   = "set-workers"
-	listFlag        = "list"
-	deleteFlag      = "delete"
-	articlesFlag    = "articles"
-	statusFlag      = "status"
+	fetchFlag        = "fetch"
+	addFlag          = "add"
+	setIntervalFlag  = "set-interval"
+	setWorkerFlag    = "set-workers"
+	setHostLimitFlag = "set-host-limit"
+	listFlag         = "list"
+	deleteFlag       = "delete"
+	articlesFlag     = "articles"
+	statusFlag       = "status"
+	enableFlag       = "enable"
+	jobsFlag         = "jobs"
+	migrateFlag      = "migrate"
+	serveFlag        = "serve"
+	importFlag       = "import"
+	exportFlag       = "export"
+	searchFlag       = "search"
+	markReadFlag     = "mark-read"
+	starFlag         = "star"
+	unstarFlag       = "unstar"
+	userFlag         = "user"
+	groupFlag        = "group"
+	rulesFlag        = "rules"
+	alertsFlag       = "alerts"
 )
 
 var (
-	nameSubFlag     = "--name"
-	feednameSubFlag = "--feed-name"
-	numSubFlag      = "--num"
-	urlSubFlag      = "--url"
-	descriptionFlag = "--desc"
+	nameSubFlag      = "--name"
+	feednameSubFlag  = "--feed-name"
+	numSubFlag       = "--num"
+	urlSubFlag       = "--url"
+	descriptionFlag  = "--desc"
+	withMediaSubFlag = "--with-media"
+	categorySubFlag  = "--category"
+	statusSubFlag    = "--status"
+	opmlSubFlag      = "--opml"
+	sinceSubFlag     = "--since"
+	beforeSubFlag    = "--before"
+	unreadSubFlag    = "--unread"
+	starredSubFlag   = "--starred"
+	groupSubFlag     = "--group"
+	fieldSubFlag     = "--field"
+	matchSubFlag     = "--match"
+	patternSubFlag   = "--pattern"
+	actionSubFlag    = "--action"
+	tagSubFlag       = "--tag"
+	webhookSubFlag   = "--webhook"
 )
+
+// Actions of the migrate command: rsshub migrate up|down [steps]|status
+var (
+	migrateUpAction     = "up"
+	migrateDownAction   = "down"
+	migrateStatusAction = "status"
+)
+
+// Actions of the group command: rsshub group create|delete <group>|add|remove <group> <feed name>|list
+var (
+	groupCreateAction = "create"
+	groupDeleteAction = "delete"
+	groupAddAction    = "add"
+	groupRemoveAction = "remove"
+	groupListAction   = "list"
+)
+
+// Actions of the rules command: rsshub rules add [rule flags]|list|delete|test <rule id>
+var (
+	rulesAddAction    = "add"
+	rulesListAction   = "list"
+	rulesDeleteAction = "delete"
+	rulesTestAction   = "test"
+)
+
+// Actions of the alerts command: rsshub alerts add [alert flags]|list|delete <alert id>|deliveries [--status <status>] [--num <num>]
+var (
+	alertsAddAction        = "add"
+	alertsListAction       = "list"
+	alertsDeleteAction     = "delete"
+	alertsDeliveriesAction = "deliveries"
+)
+
+// Actions of the user command: rsshub user add|token|grant|revoke|delete <name>|list
+var (
+	userAddAction    = "add"
+	userTokenAction  = "token"
+	userGrantAction  = "grant"
+	userRevokeAction = "revoke"
+	userDeleteAction = "delete"
+	userListAction   = "list"
+)
+
+// defaultIntervalValue resets own fetch interval of the feed: rsshub set-interval --feed-name <name> default
+var defaultIntervalValue = "default"
@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"RSSHub/internal/domain/models"
 	"RSSHub/pkg/utils"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -27,6 +32,67 @@ func (h *CLIHandler) handleFetch() error {
 		log.Error("Data fetch failed", "error", err)
 		return err
 	}
+
+	shutdownCh := make(chan os.Signal, 1)
+	signal.Notify(shutdownCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(shutdownCh)
+
+	// The aggregator also stops by itself when its instance lock is lost
+	select {
+	case s := <-shutdownCh:
+		h.log.Notify(fmt.Sprintf("catched shutdown signal %s", s.String()))
+		if err := h.aggregator.Stop(); err != nil && !errors.Is(err, models.ErrNotRunning) {
+			log.Error("Failed to stop aggregator", "error", err)
+			return err
+		}
+	case <-h.aggregator.Done():
+	}
+
+	h.log.Notify("graceful shutdown completed!")
+	return nil
+}
+
+// handleServe runs the HTTP API until SIGINT or SIGTERM, the aggregator started over the API is stopped with it
+func (h *CLIHandler) handleServe() error {
+	const op = "CLIHandler.handleServe"
+	log := h.log.GetSlogLogger().With(slog.String("op", op))
+
+	if len(h.args) != 1 {
+		log.Error("Invalid serve command usage", "expected", "rsshub serve", "got", h.args)
+		return ErrInvServeFlag
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.server.ListenAndServe()
+	}()
+	h.log.Notify(fmt.Sprintf("HTTP API is listening on %s", h.server.Addr()))
+
+	shutdownCh := make(chan os.Signal, 1)
+	signal.Notify(shutdownCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(shutdownCh)
+
+	select {
+	case s := <-shutdownCh:
+		h.log.Notify(fmt.Sprintf("catched shutdown signal %s", s.String()))
+	case err := <-errCh:
+		log.Error("HTTP server failed", "error", err)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.server.Shutdown(ctx); err != nil {
+		log.Error("Failed to shut down HTTP server", "error", err)
+	}
+
+	if err := h.aggregator.Stop(); err != nil && !errors.Is(err, models.ErrNotRunning) {
+		log.Error("Failed to stop aggregator", "error", err)
+		return err
+	}
+
+	h.log.Notify("graceful shutdown completed!")
 	return nil
 }
 
@@ -70,13 +136,15 @@ func (h *CLIHandler) handleAdd() error {
 		return ErrEmptyDesc
 	}
 
-	log.Info("Adding new feed", "name", name, "url", url, "description", desc)
-	if err := h.aggregator.AddFeed(name, desc, url); err != nil {
+	log.Info("Adding new feed", "user", h.user, "name", name, "url", url, "description", desc)
+	feed, err := h.aggregator.AddFeed(h.user, name, desc, url)
+	if err != nil {
 		log.Error("Failed to add feed", "error", err)
 		return err
 	}
 
-	msg := fmt.Sprintf("Feed %s added succesfully with URL %s", name, url)
+	// Another user may have added the URL under a different name
+	msg := fmt.Sprintf("Feed %s added succesfully with URL %s", feed.Name, feed.URL)
 	h.log.Notify(msg)
 	return nil
 }
@@ -85,14 +153,38 @@ func (h *CLIHandler) handleInterval() error {
 	const op = "CLIHandler.handleInterval"
 	log := h.log.GetSlogLogger().With(slog.String("op", op))
 
-	if len(h.args) != 2 {
-		log.Error("Invalid interval command usage", "expected", "rsshub set-interval <duration>", "got", h.args)
+	var feedName, value string
+	switch len(h.args) {
+	case 2:
+		value = h.args[1]
+	case 4:
+		if h.args[1] != feednameSubFlag {
+			log.Error("Missing required --feed-name flag", "got", h.args[1])
+			return ErrMissingFeedNameSubFlag
+		}
+		feedName, value = h.args[2], h.args[3]
+
+		if len(feedName) == 0 {
+			log.Error("Feed name flag cannot be empty")
+			return ErrEmptyFeedName
+		}
+	default:
+		log.Error("Invalid interval command usage", "expected", "rsshub set-interval [--feed-name <name>] <duration>", "got", h.args)
 		return ErrInvIntervalFlag
 	}
 
-	interval, err := time.ParseDuration(h.args[1])
+	// Feed falls back to the global interval
+	if feedName != "" && value == defaultIntervalValue {
+		if err := h.aggregator.SetFeedInterval(h.user, feedName, 0); err != nil {
+			log.Error("Failed to reset feed interval", "error", err)
+			return err
+		}
+		return nil
+	}
+
+	interval, err := time.ParseDuration(value)
 	if err != nil {
-		log.Error("Invalid duration format", "input", h.args[1], "error", err)
+		log.Error("Invalid duration format", "input", value, "error", err)
 		return err
 	}
 
@@ -101,6 +193,14 @@ func (h *CLIHandler) handleInterval() error {
 		return errors.New("invalid interval, must be at least 2 min")
 	}
 
+	if feedName != "" {
+		if err := h.aggregator.SetFeedInterval(h.user, feedName, interval); err != nil {
+			log.Error("Failed to set feed fetch interval", "error", err)
+			return err
+		}
+		return nil
+	}
+
 	if err := h.aggregator.SetInterval(interval); err != nil {
 		log.Error("Failed to set fetch interval", "error", err)
 		return err
@@ -137,6 +237,43 @@ func (h *CLIHandler) handleWorkers() error {
 	return nil
 }
 
+func (h *CLIHandler) handleHostLimit() error {
+	const op = "CLIHandler.handleHostLimit"
+	log := h.log.GetSlogLogger().With(slog.String("op", op))
+
+	if len(h.args) != 3 {
+		log.Error("Invalid host limit command usage", "expected", "rsshub set-host-limit <max concurrent> <min spacing>", "got", h.args)
+		return ErrInvHostLimitFlag
+	}
+
+	maxConcurrency, err := strconv.Atoi(h.args[1])
+	if err != nil {
+		log.Error("Invalid max concurrent requests, must be an integer", "input", h.args[1], "error", err)
+		return err
+	}
+
+	if maxConcurrency < 1 {
+		return errors.New("max concurrent requests per host must be greater than 0")
+	}
+
+	minSpacing, err := time.ParseDuration(h.args[2])
+	if err != nil {
+		log.Error("Invalid duration format", "input", h.args[2], "error", err)
+		return err
+	}
+
+	if minSpacing < 0 {
+		return errors.New("min spacing between requests must not be negative")
+	}
+
+	if err := h.aggregator.SetHostLimits(maxConcurrency, minSpacing); err != nil {
+		log.Error("Failed to set host limits", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (h *CLIHandler) handleDelete() error {
 	const op = "CLIHandler.handleDelete"
 	log := h.log.GetSlogLogger().With(slog.String("op", op))
@@ -158,7 +295,7 @@ func (h *CLIHandler) handleDelete() error {
 	}
 
 	log.Info("Deleting feed", "name", name)
-	if err := h.aggregator.DeleteFeed(name); err != nil {
+	if err := h.aggregator.DeleteFeed(h.user, name); err != nil {
 		log.Error("Failed to delete feed", "name", name, "error", err)
 		return err
 	}
@@ -167,46 +304,84 @@ func (h *CLIHandler) handleDelete() error {
 	return nil
 }
 
+func (h *CLIHandler) handleEnable() error {
+	const op = "CLIHandler.handleEnable"
+	log := h.log.GetSlogLogger().With(slog.String("op", op))
+
+	if len(h.args) != 3 {
+		log.Error("Invalid enable command usage", "expected", "rsshub enable --name <name>", "got", h.args)
+		return ErrInvEnableFlag
+	}
+
+	if h.args[1] != nameSubFlag {
+		log.Error("Missing required --name flag", "got", h.args[1])
+		return ErrMissingNameFlag
+	}
+
+	name := h.args[2]
+	if len(name) == 0 {
+		log.Error("Feed name cannot be empty")
+		return ErrEmptyName
+	}
+
+	log.Info("Enabling feed", "name", name)
+	if err := h.aggregator.EnableFeed(h.user, name); err != nil {
+		log.Error("Failed to enable feed", "name", name, "error", err)
+		return err
+	}
+
+	h.log.Notify(fmt.Sprintf("Feed %s enabled, it will be fetched on the next tick", name))
+	return nil
+}
+
 func (h *CLIHandler) handleList() error {
 	const op = "CLIHandler.handleList"
 	log := h.log.GetSlogLogger().With(
 		slog.String("op", op),
 	)
 
-	var (
-		feedCount int
-		err       error
-	)
-	switch len(h.args) {
-	case 1:
-		feedCount = 0
-	case 3:
-		if h.args[1] != numSubFlag {
-			log.Error("Missing --num flag", "got", h.args)
-			return ErrMissingNumFlag
-		}
-		feedCount, err = strconv.Atoi(h.args[2])
-		if err != nil {
-			log.Error("Invalid feed count, must be an integer", "input", h.args[2], "error", err)
-			return ErrMissingNumFlag
-		}
+	var filter models.FeedFilter
 
-		if feedCount < 1 {
-			return ErrInvNumFlag
+	args := h.args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case numSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --num value", "got", h.args)
+				return ErrMissingNumFlag
+			}
+
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error("Invalid feed count, must be an integer", "input", value, "error", err)
+				return ErrMissingNumFlag
+			}
+			if num < 1 {
+				return ErrInvNumFlag
+			}
+			filter.Num = num
+		case groupSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok || value == "" {
+				log.Error("Missing --group value", "got", h.args)
+				return ErrEmptyGroup
+			}
+			filter.Group = value
+		default:
+			log.Error("Invalid list command usage", "expected", "rsshub list [--group <name>] [--num <num>]", "got", h.args)
+			return ErrInvListFlag
 		}
-	default:
-		log.Error("Invalid list command usage", "expected", "rsshub list | rsshub list --num <num>", "got", h.args)
-		return ErrInvListFlag
 	}
 
-	log.Info("Getting feeds list", "feed count", feedCount)
-	feeds, err := h.aggregator.ListFeeds(feedCount)
+	log.Info("Getting feeds list", "group", filter.Group, "feed count", filter.Num)
+	feeds, err := h.aggregator.ListFeeds(h.user, filter)
 	if err != nil {
 		log.Error("Failed to get feeds list", "error", err)
 		return err
 	}
 
-	utils.PrintFeedsList(feeds)
+	utils.PrintFeedsList(feeds, filter.Group)
 	return nil
 }
 
@@ -216,61 +391,857 @@ func (h *CLIHandler) handleArticle() error {
 		slog.String("op", op),
 	)
 
-	feedName, num := "", 0
+	var (
+		filter       models.ArticleFilter
+		feedNameSeen bool
+		groupSeen    bool
+	)
+
+	args := h.args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case feednameSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --feed-name value", "got", h.args)
+				return ErrEmptyFeedName
+			}
+			filter.FeedName, feedNameSeen = value, true
+		case groupSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --group value", "got", h.args)
+				return ErrEmptyGroup
+			}
+			filter.Group, groupSeen = value, true
+		case numSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --num value", "got", h.args)
+				return ErrMissingNumFlag
+			}
 
-	switch len(h.args) {
-	case 3:
-		if h.args[1] != feednameSubFlag {
-			log.Error("Missing required --feed-name flag", "got", h.args)
-			return ErrMissingFeedNameSubFlag
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error("Invalid article count, must be an integer", "input", value, "error", err)
+				return ErrMissingNumFlag
+			}
+			if num < 1 {
+				return ErrInvNumFlag
+			}
+			filter.Num = num
+		case categorySubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok || value == "" {
+				log.Error("Missing --category value", "got", h.args)
+				return ErrEmptyCategory
+			}
+			filter.Category = value
+		case withMediaSubFlag:
+			filter.WithMedia = true
+		case unreadSubFlag:
+			filter.Unread = true
+		case starredSubFlag:
+			filter.Starred = true
+		default:
+			log.Error(ErrArticleFlagExpected.Error(), "got", h.args)
+			return ErrArticleFlagExpected
+		}
+	}
+
+	// Articles are of one feed or merged across feeds of the group
+	switch {
+	case feedNameSeen && groupSeen:
+		log.Error(ErrArticleFlagExpected.Error(), "got", h.args)
+		return ErrArticleFlagExpected
+	case groupSeen:
+		if len(filter.Group) == 0 {
+			log.Error("Group flag cannot be empty")
+			return ErrEmptyGroup
 		}
-		feedName = h.args[2]
+	case !feedNameSeen:
+		log.Error("Missing required --feed-name flag", "got", h.args)
+		return ErrMissingFeedNameSubFlag
+	case len(filter.FeedName) == 0:
+		log.Error("Feed name flag cannot be empty")
+		return ErrEmptyFeedName
+	}
 
-		if len(feedName) == 0 {
-			log.Error("Feed name flag cannot be empty")
-			return ErrEmptyFeedName
+	log.Info("Getting articles list", "feedName", filter.FeedName, "group", filter.Group, "num", filter.Num, "category", filter.Category, "withMedia", filter.WithMedia)
+	articles, err := h.aggregator.GetArticles(h.user, filter)
+	if err != nil {
+		log.Error("Failes to get articles", "error", err)
+		return err
+	}
+
+	if filter.WithMedia {
+		utils.PrintMediaList(articles, filter)
+		return nil
+	}
+
+	utils.PrintArticleList(articles, filter)
+	return nil
+}
+
+// beforeLayouts are accepted formats of the --before date, in local time
+var beforeLayouts = []string{time.DateOnly, time.DateTime, time.RFC3339}
+
+func (h *CLIHandler) handleMarkRead() error {
+	const op = "CLIHandler.handleMarkRead"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	var (
+		feedName string
+		before   = time.Now()
+	)
+
+	args := h.args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case feednameSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok || value == "" {
+				log.Error("Missing --feed-name value", "got", h.args)
+				return ErrEmptyFeedName
+			}
+			feedName = value
+		case beforeSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --before value", "got", h.args)
+				return ErrInvBeforeFlag
+			}
+
+			date, err := parseDate(value)
+			if err != nil {
+				log.Error("Invalid --before value", "input", value)
+				return ErrInvBeforeFlag
+			}
+			before = date
+		default:
+			log.Error(ErrMarkReadFlagExpected.Error(), "got", h.args)
+			return ErrMarkReadFlagExpected
 		}
-	case 5:
-		var err error
-		if h.args[1] != feednameSubFlag {
-			log.Error("Missing required --feed-name flag", "got", h.args)
-			return ErrMissingFeedNameSubFlag
+	}
+
+	if feedName == "" {
+		log.Error("Missing required --feed-name flag", "got", h.args)
+		return ErrMissingFeedNameSubFlag
+	}
+
+	count, err := h.aggregator.MarkRead(h.user, feedName, before)
+	if err != nil {
+		log.Error("Failed to mark articles as read", "error", err)
+		return err
+	}
+
+	h.log.Notify(fmt.Sprintf("%d articles of %s are marked as read", count, feedName))
+	return nil
+}
+
+func (h *CLIHandler) handleStar() error {
+	const op = "CLIHandler.handleStar"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) != 2 || h.args[1] == "" {
+		log.Error(ErrStarFlagExpected.Error(), "got", h.args)
+		return ErrStarFlagExpected
+	}
+
+	starred := h.args[0] == starFlag
+	if err := h.aggregator.StarArticle(h.user, h.args[1], starred); err != nil {
+		log.Error("Failed to star article", "error", err)
+		return err
+	}
+
+	if starred {
+		h.log.Notify(fmt.Sprintf("Article %s is starred", h.args[1]))
+	} else {
+		h.log.Notify(fmt.Sprintf("Article %s is unstarred", h.args[1]))
+	}
+	return nil
+}
+
+// parseDate parses a date in one of beforeLayouts
+func parseDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range beforeLayouts {
+		var date time.Time
+		if date, err = time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
 		}
-		feedName = h.args[2]
+	}
+	return time.Time{}, err
+}
 
-		if len(feedName) == 0 {
-			log.Error("Feed name flag cannot be empty")
-			return ErrEmptyFeedName
+// defaultSearchNum limits search results when --num is not given
+const defaultSearchNum = 20
+
+func (h *CLIHandler) handleSearch() error {
+	const op = "CLIHandler.handleSearch"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 || strings.TrimSpace(h.args[1]) == "" {
+		log.Error(ErrSearchFlagExpected.Error(), "got", h.args)
+		return ErrSearchFlagExpected
+	}
+
+	filter := models.SearchFilter{
+		Query: h.args[1],
+		Num:   defaultSearchNum,
+	}
+
+	args := h.args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case feednameSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok || value == "" {
+				log.Error("Missing --feed-name value", "got", h.args)
+				return ErrEmptyFeedName
+			}
+			filter.FeedName = value
+		case sinceSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --since value", "got", h.args)
+				return ErrInvSinceFlag
+			}
+
+			age, err := utils.ParseAge(value)
+			if err != nil || age <= 0 {
+				log.Error("Invalid --since value", "input", value, "error", err)
+				return ErrInvSinceFlag
+			}
+			filter.Since = time.Now().Add(-age)
+		case numSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --num value", "got", h.args)
+				return ErrMissingNumFlag
+			}
+
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error("Invalid article count, must be an integer", "input", value, "error", err)
+				return ErrMissingNumFlag
+			}
+			if num < 1 {
+				return ErrInvNumFlag
+			}
+			filter.Num = num
+		default:
+			log.Error(ErrSearchFlagExpected.Error(), "got", h.args)
+			return ErrSearchFlagExpected
 		}
+	}
+
+	results, err := h.aggregator.SearchArticles(h.user, filter)
+	if err != nil {
+		log.Error("Failed to search articles", "error", err)
+		return err
+	}
+
+	utils.PrintSearchResults(results, filter.Query)
+	return nil
+}
 
-		if h.args[3] != numSubFlag {
-			log.Error("Missing --num flag", "got", h.args)
-			return ErrMissingNumFlag
+func (h *CLIHandler) handleJobs() error {
+	const op = "CLIHandler.handleJobs"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	var filter models.JobFilter
+
+	args := h.args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case statusSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --status value", "got", h.args)
+				return ErrInvJobStatus
+			}
+
+			switch value {
+			case models.JobPending, models.JobRunning, models.JobDone, models.JobDead:
+				filter.Status = value
+			default:
+				log.Error("Invalid job status", "input", value)
+				return ErrInvJobStatus
+			}
+		case numSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --num value", "got", h.args)
+				return ErrMissingNumFlag
+			}
+
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error("Invalid job count, must be an integer", "input", value, "error", err)
+				return ErrMissingNumFlag
+			}
+			if num < 1 {
+				return ErrInvNumFlag
+			}
+			filter.Num = num
+		default:
+			log.Error(ErrJobsFlagExpected.Error(), "got", h.args)
+			return ErrJobsFlagExpected
 		}
+	}
+
+	jobs, err := h.aggregator.ListJobs(filter)
+	if err != nil {
+		log.Error("Failed to get jobs", "error", err)
+		return err
+	}
+
+	utils.PrintJobsList(jobs)
+	return nil
+}
+
+func (h *CLIHandler) handleImport() error {
+	const op = "CLIHandler.handleImport"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) != 3 || h.args[1] != opmlSubFlag || h.args[2] == "" {
+		log.Error(ErrImportFlagExpected.Error(), "got", h.args)
+		return ErrImportFlagExpected
+	}
+
+	file, err := os.Open(h.args[2])
+	if err != nil {
+		log.Error("Failed to open OPML file", "error", err)
+		return err
+	}
+	defer file.Close()
 
-		num, err = strconv.Atoi(h.args[4])
+	results, err := h.aggregator.ImportOPML(h.user, file)
+	if err != nil {
+		log.Error("Failed to import OPML file", "error", err)
+		return err
+	}
+
+	utils.PrintImportResults(results)
+	return nil
+}
+
+func (h *CLIHandler) handleExport() error {
+	const op = "CLIHandler.handleExport"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 || len(h.args) > 3 || h.args[1] != opmlSubFlag {
+		log.Error(ErrExportFlagExpected.Error(), "got", h.args)
+		return ErrExportFlagExpected
+	}
+
+	if len(h.args) == 2 {
+		return h.aggregator.ExportOPML(h.user, os.Stdout)
+	}
+
+	file, err := os.Create(h.args[2])
+	if err != nil {
+		log.Error("Failed to create OPML file", "error", err)
+		return err
+	}
+
+	if err := h.aggregator.ExportOPML(h.user, file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		log.Error("Failed to write OPML file", "error", err)
+		return err
+	}
+
+	h.log.Notify(fmt.Sprintf("Feeds are exported to %s", h.args[2]))
+	return nil
+}
+
+func (h *CLIHandler) handleGroup() error {
+	const op = "CLIHandler.handleGroup"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 {
+		log.Error(ErrGroupFlagExpected.Error(), "got", h.args)
+		return ErrGroupFlagExpected
+	}
+
+	if h.args[1] == groupListAction {
+		if len(h.args) != 2 {
+			return ErrGroupFlagExpected
+		}
+
+		groups, err := h.aggregator.ListGroups(h.user)
 		if err != nil {
-			log.Error("Invalid article count, must be an integer", "input", h.args[4], "error", err)
-			return ErrMissingNumFlag
+			log.Error("Failed to get groups list", "error", err)
+			return err
 		}
 
-		if num < 1 {
-			return ErrInvNumFlag
+		utils.PrintGroupsList(groups)
+		return nil
+	}
+
+	if len(h.args) < 3 || h.args[2] == "" {
+		log.Error(ErrGroupFlagExpected.Error(), "got", h.args)
+		return ErrGroupFlagExpected
+	}
+	group := h.args[2]
+
+	switch h.args[1] {
+	case groupCreateAction:
+		if len(h.args) != 3 {
+			return ErrGroupFlagExpected
+		}
+
+		if err := h.aggregator.CreateGroup(h.user, group); err != nil {
+			log.Error("Failed to create group", "group", group, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Group %s created", group))
+	case groupDeleteAction:
+		if len(h.args) != 3 {
+			return ErrGroupFlagExpected
+		}
+
+		if err := h.aggregator.DeleteGroup(h.user, group); err != nil {
+			log.Error("Failed to delete group", "group", group, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Group %s deleted, its feeds are kept", group))
+	case groupAddAction:
+		if len(h.args) != 4 || h.args[3] == "" {
+			return ErrGroupFlagExpected
+		}
+
+		feedName := h.args[3]
+		if err := h.aggregator.AddGroupFeed(h.user, group, feedName); err != nil {
+			log.Error("Failed to add feed to group", "group", group, "feed name", feedName, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Feed %s added to group %s", feedName, group))
+	case groupRemoveAction:
+		if len(h.args) != 4 || h.args[3] == "" {
+			return ErrGroupFlagExpected
 		}
 
+		feedName := h.args[3]
+		if err := h.aggregator.RemoveGroupFeed(h.user, group, feedName); err != nil {
+			log.Error("Failed to remove feed from group", "group", group, "feed name", feedName, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Feed %s removed from group %s", feedName, group))
 	default:
-		log.Error(ErrArticleFlagExpected.Error(), "got", h.args)
-		return ErrArticleFlagExpected
+		log.Error(ErrGroupFlagExpected.Error(), "got", h.args)
+		return ErrGroupFlagExpected
 	}
 
-	log.Info("Getting articles list", "feedName", feedName, "num", num)
-	articles, err := h.aggregator.GetArticles(feedName, num)
+	return nil
+}
+
+func (h *CLIHandler) handleRules() error {
+	const op = "CLIHandler.handleRules"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 {
+		log.Error(ErrRulesFlagExpected.Error(), "got", h.args)
+		return ErrRulesFlagExpected
+	}
+
+	switch h.args[1] {
+	case rulesAddAction:
+		return h.handleAddRule()
+	case rulesListAction:
+		if len(h.args) != 2 {
+			return ErrRulesFlagExpected
+		}
+
+		rules, err := h.aggregator.ListRules(h.user)
+		if err != nil {
+			log.Error("Failed to get rules list", "error", err)
+			return err
+		}
+
+		utils.PrintRulesList(rules)
+	case rulesDeleteAction:
+		if len(h.args) != 3 || h.args[2] == "" {
+			return ErrRulesFlagExpected
+		}
+
+		if err := h.aggregator.DeleteRule(h.user, h.args[2]); err != nil {
+			log.Error("Failed to delete rule", "rule id", h.args[2], "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Rule %s deleted", h.args[2]))
+	case rulesTestAction:
+		if len(h.args) != 3 || h.args[2] == "" {
+			return ErrRulesFlagExpected
+		}
+
+		rule, articles, err := h.aggregator.TestRule(h.user, h.args[2])
+		if errors.Is(err, models.ErrArticlesNotFound) {
+			h.log.Notify(fmt.Sprintf("Rule %s matches no stored articles", h.args[2]))
+			return nil
+		}
+		if err != nil {
+			log.Error("Failed to test rule", "rule id", h.args[2], "error", err)
+			return err
+		}
+
+		utils.PrintRuleTest(rule, articles)
+	default:
+		log.Error(ErrRulesFlagExpected.Error(), "got", h.args)
+		return ErrRulesFlagExpected
+	}
+
+	return nil
+}
+
+func (h *CLIHandler) handleAddRule() error {
+	const op = "CLIHandler.handleAddRule"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	var rule models.Rule
+
+	// Every flag takes a value
+	args := h.args[2:]
+	for i := 0; i < len(args); i++ {
+		subFlag := args[i]
+		value, ok := nextValue(args, &i)
+		if !ok || value == "" {
+			log.Error("Missing flag value", "flag", subFlag, "got", h.args)
+			return ErrRulesFlagExpected
+		}
+
+		switch subFlag {
+		case fieldSubFlag:
+			rule.Field = value
+		case matchSubFlag:
+			rule.Match = value
+		case patternSubFlag:
+			rule.Pattern = value
+		case actionSubFlag:
+			rule.Action = value
+		case tagSubFlag:
+			rule.Tag = value
+		case feednameSubFlag:
+			rule.FeedName = value
+		case groupSubFlag:
+			rule.Group = value
+		default:
+			log.Error(ErrRulesFlagExpected.Error(), "got", h.args)
+			return ErrRulesFlagExpected
+		}
+	}
+
+	created, err := h.aggregator.AddRule(h.user, &rule)
 	if err != nil {
-		log.Error("Failes to get articles", "error", err)
+		log.Error("Failed to add rule", "error", err)
 		return err
 	}
 
-	utils.PrintArticleList(articles, feedName)
+	h.log.Notify(fmt.Sprintf("Rule %s added: %s", created.ID, utils.PrettyRule(created)))
+	return nil
+}
+
+func (h *CLIHandler) handleAlerts() error {
+	const op = "CLIHandler.handleAlerts"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 {
+		log.Error(ErrAlertsFlagExpected.Error(), "got", h.args)
+		return ErrAlertsFlagExpected
+	}
+
+	switch h.args[1] {
+	case alertsAddAction:
+		return h.handleAddAlert()
+	case alertsListAction:
+		if len(h.args) != 2 {
+			return ErrAlertsFlagExpected
+		}
+
+		alerts, err := h.aggregator.ListAlerts(h.user)
+		if err != nil {
+			log.Error("Failed to get alerts list", "error", err)
+			return err
+		}
+
+		utils.PrintAlertsList(alerts)
+	case alertsDeleteAction:
+		if len(h.args) != 3 || h.args[2] == "" {
+			return ErrAlertsFlagExpected
+		}
+
+		if err := h.aggregator.DeleteAlert(h.user, h.args[2]); err != nil {
+			log.Error("Failed to delete alert", "alert id", h.args[2], "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("Alert %s deleted", h.args[2]))
+	case alertsDeliveriesAction:
+		return h.handleDeliveries()
+	default:
+		log.Error(ErrAlertsFlagExpected.Error(), "got", h.args)
+		return ErrAlertsFlagExpected
+	}
+
+	return nil
+}
+
+func (h *CLIHandler) handleAddAlert() error {
+	const op = "CLIHandler.handleAddAlert"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	alert := models.Alert{Match: models.AlertMatchKeyword}
+
+	// Every flag takes a value
+	args := h.args[2:]
+	for i := 0; i < len(args); i++ {
+		subFlag := args[i]
+		value, ok := nextValue(args, &i)
+		if !ok || value == "" {
+			log.Error("Missing flag value", "flag", subFlag, "got", h.args)
+			return ErrAlertsFlagExpected
+		}
+
+		switch subFlag {
+		case matchSubFlag:
+			alert.Match = value
+		case patternSubFlag:
+			alert.Pattern = value
+		case webhookSubFlag:
+			alert.WebhookURL = value
+		case feednameSubFlag:
+			alert.FeedName = value
+		default:
+			log.Error(ErrAlertsFlagExpected.Error(), "got", h.args)
+			return ErrAlertsFlagExpected
+		}
+	}
+
+	created, err := h.aggregator.AddAlert(h.user, &alert)
+	if err != nil {
+		log.Error("Failed to add alert", "error", err)
+		return err
+	}
+
+	// The secret is shown once, webhooks use it to verify signatures of payloads
+	h.log.Notify(fmt.Sprintf("Alert %s added: %s, signing secret: %s", created.ID, utils.PrettyAlert(created), created.Secret))
+	return nil
+}
+
+func (h *CLIHandler) handleDeliveries() error {
+	const op = "CLIHandler.handleDeliveries"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	var filter models.DeliveryFilter
+
+	args := h.args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case statusSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --status value", "got", h.args)
+				return ErrInvDeliveryStatus
+			}
+
+			switch value {
+			case models.DeliveryPending, models.DeliveryRunning, models.DeliveryDelivered, models.DeliveryFailed:
+				filter.Status = value
+			default:
+				log.Error("Invalid delivery status", "input", value)
+				return ErrInvDeliveryStatus
+			}
+		case numSubFlag:
+			value, ok := nextValue(args, &i)
+			if !ok {
+				log.Error("Missing --num value", "got", h.args)
+				return ErrMissingNumFlag
+			}
+
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error("Invalid delivery count, must be an integer", "input", value, "error", err)
+				return ErrMissingNumFlag
+			}
+			if num < 1 {
+				return ErrInvNumFlag
+			}
+			filter.Num = num
+		default:
+			log.Error(ErrAlertsFlagExpected.Error(), "got", h.args)
+			return ErrAlertsFlagExpected
+		}
+	}
+
+	deliveries, err := h.aggregator.ListDeliveries(h.user, filter)
+	if err != nil {
+		log.Error("Failed to get deliveries", "error", err)
+		return err
+	}
+
+	utils.PrintDeliveriesList(deliveries)
+	return nil
+}
+
+func (h *CLIHandler) handleUser() error {
+	const op = "CLIHandler.handleUser"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 {
+		log.Error(ErrUserFlagExpected.Error(), "got", h.args)
+		return ErrUserFlagExpected
+	}
+
+	if h.args[1] == userListAction {
+		if len(h.args) != 2 {
+			return ErrUserFlagExpected
+		}
+
+		users, err := h.aggregator.ListUsers()
+		if err != nil {
+			log.Error("Failed to get users list", "error", err)
+			return err
+		}
+
+		utils.PrintUsersList(users)
+		return nil
+	}
+
+	if len(h.args) != 3 || h.args[2] == "" {
+		log.Error(ErrUserFlagExpected.Error(), "got", h.args)
+		return ErrUserFlagExpected
+	}
+	name := h.args[2]
+
+	// The token is shown once, only its hash is stored
+	switch h.args[1] {
+	case userAddAction:
+		token, err := h.aggregator.AddUser(name)
+		if err != nil {
+			log.Error("Failed to add user", "name", name, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("User %s added, API token: %s", name, token))
+	case userTokenAction:
+		token, err := h.aggregator.ResetToken(name)
+		if err != nil {
+			log.Error("Failed to reset token", "name", name, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("New API token of %s: %s", name, token))
+	case userGrantAction, userRevokeAction:
+		admin := h.args[1] == userGrantAction
+		if err := h.aggregator.SetAdmin(name, admin); err != nil {
+			log.Error("Failed to set admin rights", "name", name, "error", err)
+			return err
+		}
+		if admin {
+			h.log.Notify(fmt.Sprintf("User %s is an admin now", name))
+		} else {
+			h.log.Notify(fmt.Sprintf("User %s is not an admin anymore", name))
+		}
+	case userDeleteAction:
+		if err := h.aggregator.DeleteUser(name); err != nil {
+			log.Error("Failed to delete user", "name", name, "error", err)
+			return err
+		}
+		h.log.Notify(fmt.Sprintf("User %s deleted", name))
+	default:
+		log.Error(ErrUserFlagExpected.Error(), "got", h.args)
+		return ErrUserFlagExpected
+	}
+
+	return nil
+}
+
+func (h *CLIHandler) handleMigrate() error {
+	const op = "CLIHandler.handleMigrate"
+	log := h.log.GetSlogLogger().With(
+		slog.String("op", op),
+	)
+
+	if len(h.args) < 2 || len(h.args) > 3 {
+		log.Error(ErrMigrateFlagExpected.Error(), "got", h.args)
+		return ErrMigrateFlagExpected
+	}
+
+	ctx := context.Background()
+
+	switch h.args[1] {
+	case migrateUpAction:
+		if len(h.args) != 2 {
+			return ErrMigrateFlagExpected
+		}
+
+		applied, err := h.migrator.Up(ctx)
+		if err != nil {
+			log.Error("Failed to apply migrations", "error", err)
+			return err
+		}
+		utils.PrintMigrations("Applied migrations", applied)
+	case migrateDownAction:
+		steps := 1
+		if len(h.args) == 3 {
+			n, err := strconv.Atoi(h.args[2])
+			if err != nil || n < 1 {
+				log.Error("Invalid number of steps, must be a positive integer", "input", h.args[2])
+				return ErrMigrateFlagExpected
+			}
+			steps = n
+		}
+
+		reverted, err := h.migrator.Down(ctx, steps)
+		if err != nil {
+			log.Error("Failed to roll back migrations", "error", err)
+			return err
+		}
+		utils.PrintMigrations("Rolled back migrations", reverted)
+	case migrateStatusAction:
+		if len(h.args) != 2 {
+			return ErrMigrateFlagExpected
+		}
+
+		status, err := h.migrator.Status(ctx)
+		if err != nil {
+			log.Error("Failed to read migrations status", "error", err)
+			return err
+		}
+		utils.PrintMigrationStatus(status)
+	default:
+		log.Error(ErrMigrateFlagExpected.Error(), "got", h.args)
+		return ErrMigrateFlagExpected
+	}
+
 	return nil
 }
 
@@ -280,14 +1251,24 @@ func (h *CLIHandler) handleStatus() error {
 		slog.String("op", op),
 	)
 
-	rssConfig, err := h.aggregator.GetConfig(context.Background())
+	status, err := h.aggregator.GetStatus(context.Background())
 	if err != nil {
-		log.Error("failed to load config", "error", err)
-		return errors.New("failed to load config")
+		log.Error("failed to load status", "error", err)
+		return errors.New("failed to load status")
 	}
 
-	msg := utils.PrettyRssConfig(rssConfig)
+	msg := utils.PrettyStatus(status)
 
 	h.log.Notify(msg)
 	return nil
 }
+
+// nextValue advances i to the value of the sub flag at args[i].
+// It reports false when the value is missing.
+func nextValue(args []string, i *int) (string, bool) {
+	if *i+1 >= len(args) {
+		return "", false
+	}
+	*i++
+	return args[*i], true
+}
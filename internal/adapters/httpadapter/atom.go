@@ -0,0 +1,147 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"html"
+	"strings"
+)
+
+// atomFeed represents the root <feed> element of an Atom 1.0 document.
+type atomFeed struct {
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle"`
+	Links    []atomLink   `xml:"link"`
+	Authors  []atomPerson `xml:"author"`
+	Entries  []atomEntry  `xml:"entry"`
+}
+
+// atomEntry represents a single <entry> of an Atom feed.
+type atomEntry struct {
+	// Media RSS elements go first, so media:content is not matched by the Content field.
+	mediaElements
+
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// atomText is an Atom text construct, its type is "text", "html" or "xhtml".
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// HTML returns the construct as an HTML fragment.
+func (t atomText) HTML() string {
+	switch t.Type {
+	case "xhtml":
+		// Markup is inlined as XML elements, usually wrapped into a single <div>
+		return strings.TrimSpace(t.Inner)
+	case "html":
+		return strings.TrimSpace(t.Text)
+	default:
+		return html.EscapeString(strings.TrimSpace(t.Text))
+	}
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// toRSSFeed maps an Atom document into the common feed model.
+func (f *atomFeed) toRSSFeed() *models.RSSFeed {
+	feed := &models.RSSFeed{
+		Format: models.FormatAtom,
+		Channel: models.Channel{
+			Title:       strings.TrimSpace(f.Title),
+			Link:        alternateLink(f.Links),
+			Description: strings.TrimSpace(f.Subtitle),
+			Item:        make([]models.RSSItem, 0, len(f.Entries)),
+		},
+	}
+
+	for _, entry := range f.Entries {
+		// Entries inherit authors of the feed
+		if len(entry.Authors) == 0 {
+			entry.Authors = f.Authors
+		}
+		feed.Channel.Item = append(feed.Channel.Item, entry.toRSSItem())
+	}
+
+	return feed
+}
+
+func (e *atomEntry) toRSSItem() models.RSSItem {
+	link := alternateLink(e.Links)
+	// Entries without links usually carry a permalink as their id
+	if link == "" && strings.HasPrefix(e.ID, "http") {
+		link = strings.TrimSpace(e.ID)
+	}
+
+	description := firstNonEmpty(e.Summary.HTML(), e.Content.HTML())
+	pubDate := firstNonEmpty(e.Published, e.Updated)
+
+	var enclosures []models.Enclosure
+	for _, l := range e.Links {
+		if l.Rel == "enclosure" {
+			enclosures = append(enclosures, models.Enclosure{
+				URL:    strings.TrimSpace(l.Href),
+				Type:   strings.TrimSpace(l.Type),
+				Length: parseLength(l.Length),
+				Kind:   models.EnclosureKindFile,
+			})
+		}
+	}
+
+	authors := make([]string, 0, len(e.Authors))
+	for _, a := range e.Authors {
+		authors = append(authors, a.Name)
+	}
+
+	categories := make([]string, 0, len(e.Categories))
+	for _, c := range e.Categories {
+		categories = append(categories, firstNonEmpty(c.Term, c.Label))
+	}
+
+	return models.RSSItem{
+		GUID:        strings.TrimSpace(e.ID),
+		Title:       strings.TrimSpace(e.Title),
+		Link:        link,
+		Description: strings.TrimSpace(description),
+		Content:     e.Content.HTML(),
+		PubDate:     strings.TrimSpace(pubDate),
+		Author:      joinAuthors(authors),
+		Categories:  categories,
+		Enclosures:  append(enclosures, e.mediaElements.enclosures()...),
+	}
+}
+
+// alternateLink returns the href of the rel="alternate" link,
+// which is also the default when rel is omitted.
+func alternateLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
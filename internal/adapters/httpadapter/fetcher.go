@@ -2,13 +2,17 @@ package httpadapter
 
 import (
 	"RSSHub/internal/domain/models"
+	"RSSHub/pkg/feeddate"
 	"context"
-	"encoding/xml"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +22,30 @@ var (
 	}
 )
 
+// ErrNotModified is returned when the server answers a conditional request with 304 Not Modified.
+var ErrNotModified = errors.New("httpadapter: feed not modified")
+
+// RetryAfterError is returned when the server is overloaded or rate limits us (429, 503),
+// Until is the time before which the host should not be requested again.
+type RetryAfterError struct {
+	StatusCode int
+	Until      time.Time
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("httpadapter: bad status code: %d, retry after %s", e.StatusCode, e.Until.Format(time.RFC3339))
+}
+
+const (
+	// maxBodySize limits the size of a downloaded feed document.
+	maxBodySize = 10 << 20
+
+	// defaultRetryAfter is the delay used when a 429 or 503 response has no usable Retry-After header.
+	defaultRetryAfter = time.Minute
+
+	acceptHeader = "application/rss+xml, application/atom+xml, application/rdf+xml, application/feed+json, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.8"
+)
+
 // HTTPClient defines the interface for making HTTP requests.
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -37,22 +65,35 @@ func NewClient(timeout time.Duration) *Adapter {
 	}
 }
 
-func (a *Adapter) FetchRSSFeed(ctx context.Context, url string) (*models.RSSFeed, error) {
+// FetchRSSFeed downloads and decodes the feed document.
+// Cache validators stored on the feed are sent as conditional headers,
+// ErrNotModified is returned when the document has not changed since the last fetch.
+func (a *Adapter) FetchRSSFeed(ctx context.Context, source *models.Feed) (*models.RSSFeed, error) {
+	url := source.URL
 	if slices.Contains(blackList, url) {
 		return nil, errors.New("BLACK LIST NIGGA")
 	}
 
-	body, err := a.fetch(ctx, url)
+	resp, err := a.fetch(ctx, url, source.ETag, source.LastModified)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	feed, err := Parse(body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("httpadapter: failed to read response body: %w", err)
+	}
+
+	feed, err := Decode(body, resp.Header.Get("Content-Type"))
 	if err != nil {
 		return nil, err
 	}
 
 	feed.CreatedAt = time.Now()
+	normalizeItems(feed)
+	feed.ETag = resp.Header.Get("ETag")
+	feed.LastModified = resp.Header.Get("Last-Modified")
 
 	if feed.Channel.Link == "" {
 		feed.Channel.Link = url
@@ -61,31 +102,115 @@ func (a *Adapter) FetchRSSFeed(ctx context.Context, url string) (*models.RSSFeed
 	return feed, nil
 }
 
-// Fetch makes a GET request to the specified URL and returns the response body.
-func (a *Adapter) fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+// normalizeItems fills the derived fields of every item:
+// the publication date falls back to the fetch time when it is missing or unparseable,
+// the GUID falls back to a hash of link and title when the feed does not provide one,
+// enclosures without URL and repeated URLs are dropped, categories are lower-cased and deduplicated.
+func normalizeItems(feed *models.RSSFeed) {
+	for i := range feed.Channel.Item {
+		item := &feed.Channel.Item[i]
+
+		item.GUID = strings.TrimSpace(item.GUID)
+		if item.GUID == "" {
+			item.GUID = fallbackGUID(item.Link, item.Title)
+			item.GUIDFallback = true
+		}
+
+		item.Enclosures = uniqueEnclosures(item.Enclosures)
+		item.Categories = normalizeCategories(item.Categories)
+
+		publishedAt, err := feeddate.Parse(item.PubDate)
+		if err != nil {
+			item.PublishedAt = feed.CreatedAt.UTC()
+			item.PubDateInvalid = strings.TrimSpace(item.PubDate) != ""
+			continue
+		}
+		item.PublishedAt = publishedAt
+	}
+}
+
+// uniqueEnclosures keeps the first enclosure for every URL.
+func uniqueEnclosures(enclosures []models.Enclosure) []models.Enclosure {
+	seen := make(map[string]bool, len(enclosures))
+	result := enclosures[:0]
+	for _, e := range enclosures {
+		if e.URL == "" || seen[e.URL] {
+			continue
+		}
+		seen[e.URL] = true
+		result = append(result, e)
+	}
+	return result
+}
+
+func normalizeCategories(categories []string) []string {
+	var result []string
+	for _, c := range categories {
+		c = strings.ToLower(strings.Join(strings.Fields(c), " "))
+		if c != "" && !slices.Contains(result, c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// fallbackGUID derives a stable identity from link and title,
+// migration 000006 computes the same value for already stored articles.
+func fallbackGUID(link, title string) string {
+	sum := sha256.Sum256([]byte(link + "\n" + title))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fetch makes a GET request to the specified URL and returns the response,
+// the caller is responsible for closing its body.
+func (a *Adapter) fetch(ctx context.Context, url, etag, lastModified string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("httpadapter: failed to create request: %w", err)
 	}
+	req.Header.Set("Accept", acceptHeader)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
 
 	resp, err := a.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("httpadapter: request failed: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, ErrNotModified
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		resp.Body.Close()
+		return nil, &RetryAfterError{
+			StatusCode: resp.StatusCode,
+			Until:      retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
 		return nil, fmt.Errorf("httpadapter: bad status code: %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return resp, nil
 }
 
-func Parse(r io.Reader) (*models.RSSFeed, error) {
-	feed := new(models.RSSFeed)
-	decoder := xml.NewDecoder(r)
-	if err := decoder.Decode(feed); err != nil {
-		return nil, fmt.Errorf("rssparser: failed to decode RSS XML: %w", err)
+// retryAfter parses the Retry-After header given either in seconds or as an HTTP date,
+// defaultRetryAfter is used when the header is missing or malformed.
+func retryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second)
 	}
-	return feed, nil
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t
+	}
+	return now.Add(defaultRetryAfter)
 }
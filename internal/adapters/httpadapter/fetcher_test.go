@@ -0,0 +1,36 @@
+package httpadapter
+
+import "testing"
+
+func TestNormalizeItemsFallbackGUID(t *testing.T) {
+	doc := `<rss version="2.0"><channel><title>Notes</title>
+		<item><title>First note</title><description>a</description></item>
+		<item><title>Second note</title><description>b</description></item>
+		<item><guid>urn:note:3</guid><title>Third note</title></item>
+	</channel></rss>`
+
+	feed, err := Decode([]byte(doc), "application/rss+xml")
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	normalizeItems(feed)
+
+	items := feed.Channel.Item
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+
+	// Items without guid and link are told apart by their titles
+	for _, item := range items[:2] {
+		if !item.GUIDFallback || item.GUID != fallbackGUID("", item.Title) {
+			t.Errorf("item %q: GUID = %q, GUIDFallback = %v, want fallback guid", item.Title, item.GUID, item.GUIDFallback)
+		}
+	}
+	if items[0].GUID == items[1].GUID {
+		t.Errorf("items without guid and link got the same GUID %q", items[0].GUID)
+	}
+
+	if items[2].GUIDFallback || items[2].GUID != "urn:note:3" {
+		t.Errorf("item with guid: GUID = %q, GUIDFallback = %v", items[2].GUID, items[2].GUIDFallback)
+	}
+}
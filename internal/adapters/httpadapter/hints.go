@@ -0,0 +1,83 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syndicationElements holds the RSS 1.0 Syndication module (sy:*) elements of a channel.
+type syndicationElements struct {
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+// rssSchedule holds RSS 2.0 channel elements telling aggregators when to fetch the feed.
+type rssSchedule struct {
+	TTL       string `xml:"ttl"` // Minutes the channel can be cached
+	SkipHours struct {
+		Hours []string `xml:"hour"` // GMT hours 0-23
+	} `xml:"skipHours"`
+	SkipDays struct {
+		Days []string `xml:"day"`
+	} `xml:"skipDays"`
+}
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+var weekdays = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// interval returns the update interval announced by sy:updatePeriod and sy:updateFrequency, 0 when absent.
+func (s syndicationElements) interval() time.Duration {
+	period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(s.UpdatePeriod))]
+	if !ok {
+		return 0
+	}
+
+	frequency, err := strconv.Atoi(strings.TrimSpace(s.UpdateFrequency))
+	if err != nil || frequency < 1 {
+		frequency = 1
+	}
+	return period / time.Duration(frequency)
+}
+
+// hints converts schedule elements into publisher hints, invalid values are ignored.
+func (s rssSchedule) hints(sy syndicationElements) models.PublisherHints {
+	var hints models.PublisherHints
+
+	if minutes, err := strconv.Atoi(strings.TrimSpace(s.TTL)); err == nil && minutes > 0 {
+		hints.TTL = time.Duration(minutes) * time.Minute
+	}
+	hints.TTL = max(hints.TTL, sy.interval())
+
+	for _, h := range s.SkipHours.Hours {
+		hour, err := strconv.Atoi(strings.TrimSpace(h))
+		// 24 is allowed by some publishers as a synonym of midnight
+		if err != nil || hour < 0 || hour > 24 {
+			continue
+		}
+		hour %= 24
+		if !slices.Contains(hints.SkipHours, hour) {
+			hints.SkipHours = append(hints.SkipHours, hour)
+		}
+	}
+	slices.Sort(hints.SkipHours)
+
+	for _, d := range s.SkipDays.Days {
+		for _, day := range weekdays {
+			if strings.EqualFold(strings.TrimSpace(d), day) && !slices.Contains(hints.SkipDays, day) {
+				hints.SkipDays = append(hints.SkipDays, day)
+			}
+		}
+	}
+
+	return hints
+}
@@ -0,0 +1,152 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// jsonFeed represents a JSON Feed 1.0/1.1 document (https://jsonfeed.org/version/1.1).
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+
+	Author  *jsonFeedAuthor  `json:"author"`
+	Authors []jsonFeedAuthor `json:"authors"`
+}
+
+type jsonFeedItem struct {
+	ID            jsonText `json:"id"` // A string by the spec, JSON Feed 1.0 publishers often send numbers
+	URL           string   `json:"url"`
+	ExternalURL   string   `json:"external_url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	ContentText   string   `json:"content_text"`
+	Summary       string   `json:"summary"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+
+	Author  *jsonFeedAuthor  `json:"author"`  // JSON Feed 1.0
+	Authors []jsonFeedAuthor `json:"authors"` // JSON Feed 1.1
+	Tags    []string         `json:"tags"`
+
+	Attachments []jsonFeedAttachment `json:"attachments"`
+}
+
+// jsonFeedAttachment is a related resource of an item, e.g. a podcast episode.
+type jsonFeedAttachment struct {
+	URL         string   `json:"url"`
+	MimeType    string   `json:"mime_type"`
+	SizeInBytes jsonText `json:"size_in_bytes"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonText is a JSON string or number kept as text, null is empty.
+type jsonText string
+
+func (t *jsonText) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = jsonText(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("expected a string or a number, got %s", data)
+	}
+	*t = jsonText(n)
+	return nil
+}
+
+// ParseJSON decodes a JSON Feed document.
+func ParseJSON(body []byte) (*models.RSSFeed, error) {
+	doc := new(jsonFeed)
+	if err := json.Unmarshal(body, doc); err != nil {
+		return nil, fmt.Errorf("rssparser: failed to decode JSON Feed: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("rssparser: unsupported JSON Feed version %q", doc.Version)
+	}
+
+	return doc.toRSSFeed(), nil
+}
+
+// toRSSFeed maps a JSON Feed document into the common feed model.
+func (f *jsonFeed) toRSSFeed() *models.RSSFeed {
+	feed := &models.RSSFeed{
+		Format: models.FormatJSON,
+		Channel: models.Channel{
+			Title:       strings.TrimSpace(f.Title),
+			Link:        strings.TrimSpace(f.HomePageURL),
+			Description: strings.TrimSpace(f.Description),
+			Item:        make([]models.RSSItem, 0, len(f.Items)),
+		},
+	}
+
+	for _, item := range f.Items {
+		// Items inherit authors of the feed
+		if item.Author == nil && len(item.Authors) == 0 {
+			item.Author, item.Authors = f.Author, f.Authors
+		}
+		feed.Channel.Item = append(feed.Channel.Item, item.toRSSItem())
+	}
+
+	return feed
+}
+
+func (i *jsonFeedItem) toRSSItem() models.RSSItem {
+	link := i.URL
+	if link == "" {
+		link = i.ExternalURL
+	}
+
+	description := firstNonEmpty(i.Summary, i.ContentText, i.ContentHTML)
+	content := firstNonEmpty(i.ContentHTML, html.EscapeString(i.ContentText))
+	pubDate := firstNonEmpty(i.DatePublished, i.DateModified)
+
+	authors := make([]string, 0, len(i.Authors)+1)
+	if i.Author != nil {
+		authors = append(authors, i.Author.Name)
+	}
+	for _, a := range i.Authors {
+		authors = append(authors, a.Name)
+	}
+
+	enclosures := make([]models.Enclosure, 0, len(i.Attachments))
+	for _, a := range i.Attachments {
+		enclosures = append(enclosures, models.Enclosure{
+			URL:    strings.TrimSpace(a.URL),
+			Type:   strings.TrimSpace(a.MimeType),
+			Length: parseLength(string(a.SizeInBytes)),
+			Kind:   models.EnclosureKindFile,
+		})
+	}
+
+	return models.RSSItem{
+		GUID:        strings.TrimSpace(string(i.ID)),
+		Title:       strings.TrimSpace(i.Title),
+		Link:        strings.TrimSpace(link),
+		Description: strings.TrimSpace(description),
+		Content:     strings.TrimSpace(content),
+		PubDate:     strings.TrimSpace(pubDate),
+		Author:      joinAuthors(authors),
+		Categories:  i.Tags,
+		Enclosures:  enclosures,
+	}
+}
@@ -0,0 +1,58 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"slices"
+	"testing"
+)
+
+func TestParseJSONItemID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"string", `"urn:item:1"`, "urn:item:1"},
+		{"integer", `1043`, "1043"},
+		{"large integer", `12345678901234567890`, "12345678901234567890"},
+		{"null", `null`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `{"version": "https://jsonfeed.org/version/1", "title": "Notes",
+				"items": [{"id": ` + tt.id + `, "title": "Note", "url": "https://example.com/1"}]}`
+
+			feed, err := ParseJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("ParseJSON() error = %v", err)
+			}
+			if got := feed.Channel.Item[0].GUID; got != tt.want {
+				t.Errorf("GUID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONAttachments(t *testing.T) {
+	doc := `{"version": "https://jsonfeed.org/version/1.1", "title": "Podcast",
+		"items": [{"id": "1", "title": "Episode 1", "url": "https://example.com/1",
+			"attachments": [
+				{"url": "https://example.com/1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1048576},
+				{"url": "https://example.com/1.m4a", "mime_type": "audio/mp4"}
+			]}]}`
+
+	feed, err := ParseJSON([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	got := feed.Channel.Item[0].Enclosures
+	want := []models.Enclosure{
+		{URL: "https://example.com/1.mp3", Type: "audio/mpeg", Length: 1048576, Kind: models.EnclosureKindFile},
+		{URL: "https://example.com/1.m4a", Type: "audio/mp4", Kind: models.EnclosureKindFile},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Enclosures = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"strconv"
+	"strings"
+)
+
+// mediaElements holds the Media RSS (https://www.rssboard.org/media-rss) children of an RSS item or Atom entry.
+type mediaElements struct {
+	MediaContents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups     []mediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// mediaGroup wraps alternative renditions of the same media, e.g. YouTube entries.
+type mediaGroup struct {
+	Contents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// enclosures converts media:content and media:thumbnail elements into enclosures.
+func (m *mediaElements) enclosures() []models.Enclosure {
+	contents := m.MediaContents
+	thumbnails := m.MediaThumbnails
+	for _, g := range m.MediaGroups {
+		contents = append(contents, g.Contents...)
+		thumbnails = append(thumbnails, g.Thumbnails...)
+	}
+
+	result := make([]models.Enclosure, 0, len(contents)+len(thumbnails))
+	for _, c := range contents {
+		mimeType := strings.TrimSpace(c.Type)
+		if mimeType == "" && c.Medium != "" {
+			// Medium is a coarse type like "video" or "audio"
+			mimeType = strings.TrimSpace(c.Medium)
+		}
+
+		result = append(result, models.Enclosure{
+			URL:    strings.TrimSpace(c.URL),
+			Type:   mimeType,
+			Length: parseLength(c.FileSize),
+			Kind:   models.EnclosureKindMedia,
+		})
+	}
+
+	for _, t := range thumbnails {
+		result = append(result, models.Enclosure{
+			URL:  strings.TrimSpace(t.URL),
+			Kind: models.EnclosureKindThumbnail,
+		})
+	}
+
+	return result
+}
+
+// parseLength parses a size in bytes, feeds often leave it empty or put garbage there.
+func parseLength(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"strings"
+)
+
+// Decode dispatches the feed document to the decoder matching its format.
+// The Content-Type header is checked first, the body itself is sniffed when the header is missing or generic.
+func Decode(body []byte, contentType string) (*models.RSSFeed, error) {
+	if isJSONFeed(body, contentType) {
+		return ParseJSON(body)
+	}
+	return Parse(bytes.NewReader(body))
+}
+
+// isJSONFeed reports whether the document should be decoded as JSON Feed.
+func isJSONFeed(body []byte, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		switch {
+		case mediaType == "application/feed+json", mediaType == "application/json":
+			return true
+		case strings.Contains(mediaType, "xml"):
+			return false
+		}
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// Parse decodes an XML feed document, detecting its format by the root element.
+// RSS 2.0 (<rss>), RSS 1.0 (<rdf:RDF>) and Atom 1.0 (<feed>) documents are supported.
+func Parse(r io.Reader) (*models.RSSFeed, error) {
+	decoder := xml.NewDecoder(r)
+
+	root, err := rootElement(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("rssparser: failed to read root element: %w", err)
+	}
+
+	switch root.Name.Local {
+	case "rss":
+		rss := new(rssFeed)
+		if err := decoder.DecodeElement(rss, &root); err != nil {
+			return nil, fmt.Errorf("rssparser: failed to decode RSS XML: %w", err)
+		}
+		return rss.toRSSFeed(), nil
+	case "RDF":
+		rdf := new(rdfFeed)
+		if err := decoder.DecodeElement(rdf, &root); err != nil {
+			return nil, fmt.Errorf("rssparser: failed to decode RDF XML: %w", err)
+		}
+		return rdf.toRSSFeed(), nil
+	case "feed":
+		atom := new(atomFeed)
+		if err := decoder.DecodeElement(atom, &root); err != nil {
+			return nil, fmt.Errorf("rssparser: failed to decode Atom XML: %w", err)
+		}
+		return atom.toRSSFeed(), nil
+	default:
+		return nil, fmt.Errorf("rssparser: unsupported root element <%s>", root.Name.Local)
+	}
+}
+
+// rootElement skips the prolog (declarations, comments, whitespace) and returns the first start element.
+func rootElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// joinAuthors joins non-empty unique author names with a comma.
+func joinAuthors(names []string) string {
+	var unique []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return strings.Join(unique, ", ")
+}
+
+// firstNonEmpty returns the first value that is not blank.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"strings"
+)
+
+// rdfFeed represents the root <rdf:RDF> element of an RSS 1.0 document,
+// where items are siblings of the channel rather than its children.
+type rdfFeed struct {
+	Channel rdfChannel `xml:"channel"`
+	Items   []rdfItem  `xml:"item"`
+}
+
+type rdfChannel struct {
+	syndicationElements
+
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+}
+
+type rdfItem struct {
+	About       string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects    []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+// toRSSFeed maps an RSS 1.0 document into the common feed model.
+func (f *rdfFeed) toRSSFeed() *models.RSSFeed {
+	feed := &models.RSSFeed{
+		Format: models.FormatRDF,
+		Hints:  models.PublisherHints{TTL: f.Channel.syndicationElements.interval()},
+		Channel: models.Channel{
+			Title:       strings.TrimSpace(f.Channel.Title),
+			Link:        strings.TrimSpace(f.Channel.Link),
+			Description: strings.TrimSpace(f.Channel.Description),
+			Item:        make([]models.RSSItem, 0, len(f.Items)),
+		},
+	}
+
+	for _, item := range f.Items {
+		feed.Channel.Item = append(feed.Channel.Item, models.RSSItem{
+			GUID:        strings.TrimSpace(item.About),
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: strings.TrimSpace(item.Description),
+			Content:     strings.TrimSpace(item.Content),
+			PubDate:     strings.TrimSpace(item.Date),
+			Author:      joinAuthors(item.Creators),
+			Categories:  item.Subjects,
+		})
+	}
+
+	return feed
+}
@@ -0,0 +1,102 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"strings"
+)
+
+// rssFeed represents the root <rss> element of an RSS 2.0 document.
+type rssFeed struct {
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	rssSchedule
+	syndicationElements
+
+	Title       string    `xml:"title"`
+	Links       []rssLink `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+// rssLink matches both <link> and <atom:link>, only the former carries the link as text.
+type rssLink struct {
+	Value string `xml:",chardata"`
+}
+
+type rssItem struct {
+	// Media RSS elements go first, so media:* children are not matched by the generic fields below.
+	mediaElements
+
+	GUID        string         `xml:"guid"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string         `xml:"pubDate"`
+	Author      string         `xml:"author"`
+	Creators    []string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// toRSSFeed maps an RSS 2.0 document into the common feed model.
+func (f *rssFeed) toRSSFeed() *models.RSSFeed {
+	feed := &models.RSSFeed{
+		Format: models.FormatRSS,
+		Hints:  f.Channel.rssSchedule.hints(f.Channel.syndicationElements),
+		Channel: models.Channel{
+			Title:       strings.TrimSpace(f.Channel.Title),
+			Link:        f.Channel.link(),
+			Description: strings.TrimSpace(f.Channel.Description),
+			Item:        make([]models.RSSItem, 0, len(f.Channel.Items)),
+		},
+	}
+
+	for _, item := range f.Channel.Items {
+		feed.Channel.Item = append(feed.Channel.Item, item.toRSSItem())
+	}
+
+	return feed
+}
+
+// link returns the first non-empty <link> text, skipping <atom:link> self references.
+func (c *rssChannel) link() string {
+	for _, l := range c.Links {
+		if v := strings.TrimSpace(l.Value); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (i *rssItem) toRSSItem() models.RSSItem {
+	enclosures := make([]models.Enclosure, 0, len(i.Enclosures))
+	for _, e := range i.Enclosures {
+		enclosures = append(enclosures, models.Enclosure{
+			URL:    strings.TrimSpace(e.URL),
+			Type:   strings.TrimSpace(e.Type),
+			Length: parseLength(e.Length),
+			Kind:   models.EnclosureKindFile,
+		})
+	}
+
+	return models.RSSItem{
+		GUID:        strings.TrimSpace(i.GUID),
+		Title:       strings.TrimSpace(i.Title),
+		Link:        strings.TrimSpace(i.Link),
+		Description: strings.TrimSpace(i.Description),
+		Content:     strings.TrimSpace(i.Content),
+		PubDate:     strings.TrimSpace(i.PubDate),
+		Author:      joinAuthors(append([]string{i.Author}, i.Creators...)),
+		Categories:  i.Categories,
+		Enclosures:  append(enclosures, i.mediaElements.enclosures()...),
+	}
+}
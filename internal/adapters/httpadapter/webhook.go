@@ -0,0 +1,195 @@
+package httpadapter
+
+import (
+	"RSSHub/internal/domain/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// signatureHeader carries "sha256=" and the hex HMAC-SHA256 of the request body keyed with the alert secret.
+	signatureHeader = "X-RSSHub-Signature"
+	// deliveryHeader carries the id of the delivery, it is the same for every attempt.
+	deliveryHeader = "X-RSSHub-Delivery"
+)
+
+// ErrForbiddenAddress is returned when a webhook host is a loopback, private or link-local address,
+// webhooks are given by users and must not reach services of the internal network.
+var ErrForbiddenAddress = errors.New("httpadapter: webhook address is not public")
+
+// NewWebhookClient creates an HTTP adapter for webhooks. It connects to public addresses only,
+// so hosts resolving to internal ones after the check of the alert are refused too, and does not follow redirects.
+func NewWebhookClient(timeout time.Duration) *Adapter {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Adapter{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Redirects are answers of the webhook, a 3xx status fails the attempt
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// CheckWebhookURL resolves the host of the webhook URL and fails with ErrForbiddenAddress
+// when any of its addresses is not public.
+func CheckWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("httpadapter: failed to resolve webhook host: %w", err)
+	}
+
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// publicAddr reports whether the address is not a loopback, private, link-local, multicast or unspecified one
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// WebhookStatusError is returned when the webhook answers with a status other than 2xx.
+type WebhookStatusError struct {
+	StatusCode int
+}
+
+func (e *WebhookStatusError) Error() string {
+	return fmt.Sprintf("httpadapter: webhook answered with status code: %d", e.StatusCode)
+}
+
+// webhookPayload is the JSON body posted to webhooks of alerts.
+type webhookPayload struct {
+	DeliveryID string         `json:"delivery_id"`
+	Attempt    int            `json:"attempt"`
+	Alert      webhookAlert   `json:"alert"`
+	Article    webhookArticle `json:"article"`
+}
+
+type webhookAlert struct {
+	ID      string `json:"id"`
+	Match   string `json:"match"`
+	Pattern string `json:"pattern"`
+}
+
+type webhookArticle struct {
+	ID          string    `json:"id"`
+	Feed        string    `json:"feed"`
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description"`
+	Author      string    `json:"author,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// SendWebhook posts the article of the claimed delivery to the webhook of its alert.
+// The status code of the response is returned, it is 0 when no response was received.
+func (a *Adapter) SendWebhook(ctx context.Context, delivery *models.AlertDelivery) (int, error) {
+	alert, article := delivery.Alert, delivery.Article
+
+	body, err := json.Marshal(webhookPayload{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		Alert: webhookAlert{
+			ID:      alert.ID,
+			Match:   alert.Match,
+			Pattern: alert.Pattern,
+		},
+		Article: webhookArticle{
+			ID:          article.ID,
+			Feed:        article.FeedName,
+			Title:       article.Title,
+			Link:        article.Link,
+			Description: article.Description,
+			Author:      article.Author,
+			Categories:  article.Categories,
+			PublishedAt: article.PublishedAt,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("httpadapter: failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alert.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("httpadapter: failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(deliveryHeader, delivery.ID)
+	req.Header.Set(signatureHeader, "sha256="+sign(alert.Secret, body))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("httpadapter: webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drained, so the connection may be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &WebhookStatusError{StatusCode: resp.StatusCode}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// sign returns the hex HMAC-SHA256 of the body keyed with the secret, receivers compare it with the signature header.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
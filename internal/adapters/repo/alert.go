@@ -0,0 +1,356 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrAlertNotFound = errors.New("alert not found")
+
+// alertColumns are the columns of alerts al left joined with subscriptions af of their owners to the feeds of their scope,
+// read by alertFields. Secrets are not read, they are only returned on creation and to the dispatcher.
+const alertColumns = `al.id, al.user_id, COALESCE(af.name, ''), al.match, al.pattern, al.webhook_url, al.created_at`
+
+func alertFields(alert *models.Alert) []any {
+	return []any{
+		&alert.ID,
+		&alert.UserID,
+		&alert.FeedName,
+		&alert.Match,
+		&alert.Pattern,
+		&alert.WebhookURL,
+		&alert.CreatedAt,
+	}
+}
+
+// deliveryColumns are the columns of alert_deliveries d, read by deliveryFields
+const deliveryColumns = `d.id, d.alert_id, d.status, d.attempts, d.max_attempts, d.run_at,
+			COALESCE(d.response_status, 0), COALESCE(d.last_error, ''), d.created_at, d.updated_at`
+
+func deliveryFields(delivery *models.AlertDelivery) []any {
+	return []any{
+		&delivery.ID,
+		&delivery.AlertID,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.MaxAttempts,
+		&delivery.RunAt,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	}
+}
+
+type AlertRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAlertRepo(pool *pgxpool.Pool) *AlertRepo {
+	return &AlertRepo{
+		pool: pool,
+	}
+}
+
+// Create creates the alert of its user scoped to the feed with the given id, empty id means any subscribed feed
+func (r *AlertRepo) Create(ctx context.Context, alert *models.Alert, feedID string) error {
+	const op = "AlertRepo.Create"
+
+	query := `
+		INSERT INTO alerts(user_id, feed_id, match, pattern, webhook_url, secret)
+		VALUES ($1, NULLIF($2, '')::UUID, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := r.pool.QueryRow(ctx, query,
+		alert.UserID,
+		feedID,
+		alert.Match,
+		alert.Pattern,
+		alert.WebhookURL,
+		alert.Secret,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// List fetches alerts of the user in the order they were created
+func (r *AlertRepo) List(ctx context.Context, userID string) ([]*models.Alert, error) {
+	const op = "AlertRepo.List"
+
+	query := `
+		SELECT ` + alertColumns + `
+		FROM alerts al
+		LEFT JOIN subscriptions af ON af.feed_id = al.feed_id AND af.user_id = al.user_id
+		WHERE al.user_id = $1
+		ORDER BY al.created_at`
+
+	return r.list(ctx, op, query, userID)
+}
+
+// ListForFeed fetches alerts of the feed subscribers scoped to the feed or to any subscribed feed
+func (r *AlertRepo) ListForFeed(ctx context.Context, feedID string) ([]*models.Alert, error) {
+	const op = "AlertRepo.ListForFeed"
+
+	query := `
+		SELECT ` + alertColumns + `
+		FROM alerts al
+		LEFT JOIN subscriptions af ON af.feed_id = al.feed_id AND af.user_id = al.user_id
+		JOIN subscriptions s ON s.user_id = al.user_id AND s.feed_id = $1
+		WHERE al.feed_id = $1 OR al.feed_id IS NULL
+		ORDER BY al.created_at`
+
+	return r.list(ctx, op, query, feedID)
+}
+
+func (r *AlertRepo) list(ctx context.Context, op, query string, args ...any) ([]*models.Alert, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	alerts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Alert, error) {
+		var alert models.Alert
+		if err := row.Scan(alertFields(&alert)...); err != nil {
+			return nil, err
+		}
+		return &alert, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return alerts, nil
+}
+
+// Delete deletes the alert of the user by id with its deliveries
+func (r *AlertRepo) Delete(ctx context.Context, userID, id string) error {
+	const op = "AlertRepo.Delete"
+
+	// Ids are passed by users, anything but a UUID can't be an alert
+	if !uuidPattern.MatchString(id) {
+		return fmt.Errorf("%s: %w", op, ErrAlertNotFound)
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM alerts WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrAlertNotFound)
+	}
+
+	return nil
+}
+
+// Enqueue creates pending deliveries of the articles for the alerts, alertIDs and articleIDs are pairs by index.
+// An article is delivered once per alert, existing deliveries are skipped. The number of created deliveries is returned.
+func (r *AlertRepo) Enqueue(ctx context.Context, alertIDs, articleIDs []string, maxAttempts int) (int64, error) {
+	const op = "AlertRepo.Enqueue"
+
+	query := `
+		INSERT INTO alert_deliveries(alert_id, article_id, max_attempts)
+		SELECT alert_id, article_id, $3::INT FROM UNNEST($1::UUID[], $2::UUID[]) AS d(alert_id, article_id)
+		ON CONFLICT (alert_id, article_id) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, alertIDs, articleIDs, maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Claim marks up to limit due pending deliveries as running and returns them with their alerts, secrets included,
+// and articles. Deliveries locked by a concurrent claim are skipped.
+func (r *AlertRepo) Claim(ctx context.Context, limit int) ([]*models.AlertDelivery, error) {
+	const op = "AlertRepo.Claim"
+
+	query := `
+		WITH d AS (
+			UPDATE alert_deliveries
+			SET status = 'running',
+				attempts = attempts + 1,
+				locked_at = NOW(),
+				updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM alert_deliveries
+				WHERE status = 'pending' AND run_at <= NOW()
+				ORDER BY run_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT ` + deliveryColumns + `,
+			al.user_id, al.match, al.pattern, al.webhook_url, al.secret,
+			` + articleColumns + `
+		FROM d
+		JOIN alerts al ON al.id = d.alert_id
+		JOIN articles a ON a.id = d.article_id
+		JOIN feeds f ON f.id = a.feed_id
+		LEFT JOIN subscriptions sub ON sub.feed_id = f.id AND sub.user_id = al.user_id
+		LEFT JOIN article_states s ON s.article_id = a.id AND s.user_id = al.user_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.AlertDelivery, error) {
+		delivery := &models.AlertDelivery{Alert: new(models.Alert), Article: new(models.RSSItem)}
+		alert := delivery.Alert
+
+		fields := deliveryFields(delivery)
+		fields = append(fields, &alert.UserID, &alert.Match, &alert.Pattern, &alert.WebhookURL, &alert.Secret)
+		fields = append(fields, articleFields(delivery.Article)...)
+		if err := row.Scan(fields...); err != nil {
+			return nil, err
+		}
+		alert.ID = delivery.AlertID
+		return delivery, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// Complete marks the delivery as delivered with the status the webhook answered
+func (r *AlertRepo) Complete(ctx context.Context, id string, responseStatus int) error {
+	const op = "AlertRepo.Complete"
+
+	query := `
+		UPDATE alert_deliveries
+		SET status = 'delivered',
+			locked_at = NULL,
+			response_status = $2,
+			last_error = NULL,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, responseStatus); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Retry stores the outcome of the failed attempt and schedules the delivery to run again at runAt,
+// responseStatus is 0 when no response was received. The delivery fails when all its attempts are used,
+// the new failed state is returned.
+func (r *AlertRepo) Retry(ctx context.Context, id string, responseStatus int, lastError string, runAt time.Time) (bool, error) {
+	const op = "AlertRepo.Retry"
+
+	query := `
+		UPDATE alert_deliveries
+		SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+			run_at = $4,
+			locked_at = NULL,
+			response_status = NULLIF($2, 0),
+			last_error = $3,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING status = 'failed'
+	`
+
+	var failed bool
+	if err := r.pool.QueryRow(ctx, query, id, responseStatus, lastError, runAt.UTC()).Scan(&failed); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failed, nil
+}
+
+// RequeueStuck returns deliveries running longer than timeout to the queue, e.g. after a crash of the instance
+// posting them. Stuck deliveries without remaining attempts fail. The number of affected deliveries is returned.
+func (r *AlertRepo) RequeueStuck(ctx context.Context, timeout time.Duration) (int64, error) {
+	const op = "AlertRepo.RequeueStuck"
+
+	query := `
+		UPDATE alert_deliveries
+		SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+			run_at = NOW(),
+			locked_at = NULL,
+			last_error = 'delivery was not finished in time',
+			updated_at = NOW()
+		WHERE status = 'running' AND locked_at < NOW() - $1::INTERVAL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ListDeliveries fetches deliveries matching the filter with their alerts and articles, the most recent first
+func (r *AlertRepo) ListDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]*models.AlertDelivery, error) {
+	const op = "AlertRepo.ListDeliveries"
+
+	query := `
+		SELECT ` + deliveryColumns + `,
+			` + alertColumns + `,
+			` + articleColumns + `
+		FROM alert_deliveries d
+		JOIN alerts al ON al.id = d.alert_id
+		LEFT JOIN subscriptions af ON af.feed_id = al.feed_id AND af.user_id = al.user_id
+		JOIN articles a ON a.id = d.article_id
+		JOIN feeds f ON f.id = a.feed_id
+		LEFT JOIN subscriptions sub ON sub.feed_id = f.id AND sub.user_id = al.user_id
+		` + articleStates(1) + `
+		WHERE al.user_id = $1`
+
+	args := []any{filter.UserID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND d.status = $%d", len(args))
+	}
+
+	query += `
+		ORDER BY d.created_at DESC`
+
+	if filter.Num > 0 {
+		args = append(args, filter.Num)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.AlertDelivery, error) {
+		delivery := &models.AlertDelivery{Alert: new(models.Alert), Article: new(models.RSSItem)}
+
+		fields := deliveryFields(delivery)
+		fields = append(fields, alertFields(delivery.Alert)...)
+		fields = append(fields, articleFields(delivery.Article)...)
+		if err := row.Scan(fields...); err != nil {
+			return nil, err
+		}
+		return delivery, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
@@ -3,12 +3,66 @@ package repo
 import (
 	"RSSHub/internal/domain/models"
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// articleColumns are the columns of articles a joined with feeds f, subscriptions sub and left joined with article_states s,
+// read by articleFields. Feed name is the one of the subscription, empty when the user is not subscribed anymore.
+// Categories are the ones of the feed with tags added by rules of the user
+const articleColumns = `a.id, COALESCE(sub.name, ''), a.guid, a.title, a.link, a.description,
+			COALESCE(a.content, ''), COALESCE(a.author, ''),
+			ARRAY(
+				SELECT c.name FROM article_categories ac
+				JOIN categories c ON ac.category_id = c.id
+				WHERE ac.article_id = a.id
+				UNION
+				SELECT t FROM UNNEST(s.tags) t
+				ORDER BY 1
+			),
+			a.published_at, COALESCE(a.unparsed_pub_date, ''),
+			COALESCE(s.read, FALSE), COALESCE(s.starred, FALSE)`
+
+// articleStates left joins article_states s of the user in the given parameter to articles a
+func articleStates(param int) string {
+	return fmt.Sprintf(`LEFT JOIN article_states s ON s.article_id = a.id AND s.user_id = $%d`, param)
+}
+
+// subscription joins subscriptions sub of the user in the given parameter to feeds f, so only subscribed feeds are found
+func subscription(param int) string {
+	return fmt.Sprintf(`JOIN subscriptions sub ON sub.feed_id = f.id AND sub.user_id = $%d`, param)
+}
+
+var ErrArticleNotFound = errors.New("article not found")
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// articleFields returns destinations for the columns of articleColumns, in the same order
+func articleFields(item *models.RSSItem) []any {
+	return []any{
+		&item.ID,
+		&item.FeedName,
+		&item.GUID,
+		&item.Title,
+		&item.Link,
+		&item.Description,
+		&item.Content,
+		&item.Author,
+		&item.Categories,
+		&item.PublishedAt,
+		&item.PubDate,
+		&item.Read,
+		&item.Starred,
+	}
+}
+
 type ArticleRepo struct {
 	pool *pgxpool.Pool
 }
@@ -19,81 +73,322 @@ func NewArticleRepo(pool *pgxpool.Pool) *ArticleRepo {
 	}
 }
 
-// CreateOrUpdate atomically inserts multiple articles for a feed using batch operations
+// CreateOrUpdate atomically inserts multiple articles and their enclosures for a feed using batch operations.
+// Ids of the stored articles are set on them, inserted articles are marked as new.
 func (r *ArticleRepo) CreateOrUpdate(ctx context.Context, feedID string, articles []models.RSSItem) error {
 	const op = "ArticleRepo.CreateOrUpdate"
 
-	// Use ON CONFLICT to handle duplicates and update existing records
+	// Use ON CONFLICT to handle duplicates and update existing records,
+	// articles are identified by their guid within the feed.
+	// Fetch time replacing a missing or unparseable date ($10) doesn't move the stored date,
+	// otherwise undated articles would get to the top of the lists on every fetch
 	query := `
         INSERT INTO articles(
-            title, 
-            link, 
-            description, 
-            published_at, 
+            guid,
+            title,
+            link,
+            description,
+            content,
+            author,
+            published_at,
+            unparsed_pub_date,
             feed_id,
             updated_at
         ) VALUES (
-            $1, $2, $3, $4, $5, NOW()
+            $1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, NOW()
         )
-        ON CONFLICT (link) DO UPDATE SET
+        ON CONFLICT (feed_id, guid) DO UPDATE SET
             title = EXCLUDED.title,
+            link = EXCLUDED.link,
             description = EXCLUDED.description,
-            published_at = EXCLUDED.published_at,
+            content = EXCLUDED.content,
+            author = EXCLUDED.author,
+            published_at = CASE WHEN $10::BOOLEAN THEN articles.published_at ELSE EXCLUDED.published_at END,
+            unparsed_pub_date = EXCLUDED.unparsed_pub_date,
+            guid_backfilled = FALSE,
             updated_at = NOW()
-        WHERE articles.link = EXCLUDED.link`
+        RETURNING id, (xmax = 0)`
+
+	// Categories are shared by feeds, they are created out of the transaction of the feed,
+	// so concurrent fetches don't hold locks on them
+	categoryIDs, err := r.createCategories(ctx, articles)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.rekeyBackfilled(ctx, tx, feedID, articles); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	batch := &pgx.Batch{}
 
 	for _, article := range articles {
+		// Raw date is kept only for items whose date could not be parsed
+		var unparsedPubDate *string
+		if article.PubDateInvalid {
+			unparsedPubDate = &article.PubDate
+		}
+
 		batch.Queue(query,
+			article.GUID,
 			article.Title,
 			article.Link,
 			article.Description,
-			article.PubDate,
+			article.Content,
+			article.Author,
+			article.PublishedAt,
+			unparsedPubDate,
 			feedID,
+			article.PubDateInvalid || article.PubDate == "",
 		)
 	}
 
-	br := r.pool.SendBatch(ctx, batch)
-	defer br.Close()
+	br := tx.SendBatch(ctx, batch)
 
-	_, err := br.Exec()
-	if err != nil {
+	// Reading every result, so a failure of any article is reported
+	// Rows inserted by the statement have no deleting transaction, updated ones have
+	ids := make([]string, len(articles))
+	for i := range articles {
+		if err := br.QueryRow().Scan(&ids[i], &articles[i].New); err != nil {
+			br.Close()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		articles[i].ID = ids[i]
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.createEnclosures(ctx, tx, ids, articles); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.linkCategories(ctx, tx, ids, articles, categoryIDs); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
 
-// List fetches recent articles by feed name with limit
-func (r *ArticleRepo) List(ctx context.Context, feedName string, limit int) ([]*models.RSSItem, error) {
+// rekeyBackfilled gives guids of publishers to the articles backfilled by migration 000006 with the fallback guid,
+// so they are updated instead of inserted again. Publishers guids were not stored before, a backfilled article
+// is found by the link of the fetched item, or by its fallback guid when the item has no link.
+// Items without a publisher guid are matched by the fallback guid itself and never re-key other articles.
+func (r *ArticleRepo) rekeyBackfilled(ctx context.Context, tx pgx.Tx, feedID string, articles []models.RSSItem) error {
+	guids, links, titles := rekeyCandidates(articles)
+	if len(guids) == 0 {
+		return nil
+	}
+
+	// Every guid and every backfilled article is used once, so no two articles get merged
+	query := `
+        WITH k AS (
+            SELECT DISTINCT ON (guid) guid, link, title
+            FROM UNNEST($2::TEXT[], $3::TEXT[], $4::TEXT[]) AS k(guid, link, title)
+            WHERE NOT EXISTS (SELECT 1 FROM articles WHERE feed_id = $1 AND guid = k.guid)
+            ORDER BY guid
+        ), t AS (
+            SELECT DISTINCT ON (b.id) b.id, k.guid
+            FROM k
+            CROSS JOIN LATERAL (
+                SELECT id FROM articles
+                WHERE feed_id = $1 AND guid_backfilled AND link = k.link
+                    AND (k.link <> '' OR guid = encode(sha256(convert_to(k.link || E'\n' || k.title, 'UTF8')), 'hex'))
+                ORDER BY published_at DESC
+                LIMIT 1
+            ) b
+            ORDER BY b.id, k.guid
+        )
+        UPDATE articles a SET guid = t.guid, guid_backfilled = FALSE
+        FROM t
+        WHERE a.id = t.id`
+
+	if _, err := tx.Exec(ctx, query, feedID, guids, links, titles); err != nil {
+		return err
+	}
+	return nil
+}
+
+// rekeyCandidates returns guids, links and titles of the articles carrying guids of their publishers
+func rekeyCandidates(articles []models.RSSItem) (guids, links, titles []string) {
+	for _, article := range articles {
+		if article.GUIDFallback {
+			continue
+		}
+		guids = append(guids, article.GUID)
+		links = append(links, article.Link)
+		titles = append(titles, article.Title)
+	}
+	return guids, links, titles
+}
+
+// createEnclosures upserts enclosures of the articles, ids are the stored article ids in the same order.
+func (r *ArticleRepo) createEnclosures(ctx context.Context, tx pgx.Tx, ids []string, articles []models.RSSItem) error {
+	query := `
+        INSERT INTO article_enclosures(
+            article_id,
+            url,
+            mime_type,
+            length,
+            kind
+        ) VALUES (
+            $1, $2, NULLIF($3, ''), NULLIF($4::BIGINT, 0), $5
+        )
+        ON CONFLICT (article_id, url) DO UPDATE SET
+            mime_type = EXCLUDED.mime_type,
+            length = EXCLUDED.length,
+            kind = EXCLUDED.kind`
+
+	batch := &pgx.Batch{}
+	for i, article := range articles {
+		for _, e := range article.Enclosures {
+			batch.Queue(query, ids[i], e.URL, e.Type, e.Length, e.Kind)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// createCategories creates missing categories of the articles and returns ids of all of them by name.
+// Names are inserted in sorted order by a single statement, so concurrent fetches wait for each other instead of deadlocking.
+func (r *ArticleRepo) createCategories(ctx context.Context, articles []models.RSSItem) (map[string]string, error) {
+	var names []string
+	for _, article := range articles {
+		names = append(names, article.Categories...)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	query := `
+        INSERT INTO categories(name)
+        SELECT name FROM UNNEST($1::TEXT[]) AS c(name)
+        ORDER BY name
+        ON CONFLICT (name) DO NOTHING`
+
+	if _, err := r.pool.Exec(ctx, query, names); err != nil {
+		return nil, err
+	}
+
+	// Read by a new statement, so categories created by concurrent fetches are seen too
+	rows, err := r.pool.Query(ctx, `SELECT name, id FROM categories WHERE name = ANY($1)`, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string, len(names))
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		ids[name] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// linkCategories links the articles with their categories, ids are the stored article ids in the same order.
+func (r *ArticleRepo) linkCategories(ctx context.Context, tx pgx.Tx, ids []string, articles []models.RSSItem, categoryIDs map[string]string) error {
+	query := `
+        INSERT INTO article_categories(article_id, category_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`
+
+	batch := &pgx.Batch{}
+	for i, article := range articles {
+		for _, name := range article.Categories {
+			batch.Queue(query, ids[i], categoryIDs[name])
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// List fetches recent articles matching the filter, ordered by publication date
+func (r *ArticleRepo) List(ctx context.Context, filter models.ArticleFilter) ([]*models.RSSItem, error) {
 	const op = "ArticleRepo.List"
 
+	var (
+		conditions = []string{"NOT COALESCE(s.hidden, FALSE)"}
+		args       = []any{filter.UserID}
+	)
+
+	if filter.FeedName != "" {
+		args = append(args, filter.FeedName)
+		conditions = append(conditions, fmt.Sprintf("sub.name = $%d", len(args)))
+	}
+
+	if filter.Group != "" {
+		args = append(args, filter.Group)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM group_feeds gf
+				JOIN groups g ON gf.group_id = g.id
+				WHERE gf.feed_id = f.id AND g.user_id = $1 AND g.name = $%d)`, len(args)))
+	}
+
+	if filter.WithMedia {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM article_enclosures e WHERE e.article_id = a.id)")
+	}
+
+	if filter.Unread {
+		conditions = append(conditions, "NOT COALESCE(s.read, FALSE)")
+	}
+
+	if filter.Starred {
+		conditions = append(conditions, "COALESCE(s.starred, FALSE)")
+	}
+
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conditions = append(conditions, fmt.Sprintf(`(EXISTS (
+				SELECT 1 FROM article_categories ac
+				JOIN categories c ON ac.category_id = c.id
+				WHERE ac.article_id = a.id AND c.name = lower($%d))
+			OR lower($%[1]d) = ANY(s.tags))`, len(args)))
+	}
+
 	query := `
-		SELECT 
-			a.title, 
-			a.link, 
-			a.description, 
-			a.published_at::TEXT 
-		FROM 
+		SELECT ` + articleColumns + `
+		FROM
 			articles a
 		JOIN feeds f ON a.feed_id = f.id
-		WHERE 
-			f.name = $1
-		ORDER BY 
+		` + subscription(1) + `
+		` + articleStates(1) + `
+		WHERE
+			` + strings.Join(conditions, " AND ") + `
+		ORDER BY
 			a.published_at DESC`
 
-	var rows pgx.Rows
-	var err error
-
 	// Добавляем LIMIT только если он положительный
-	if limit > 0 {
-		query += " LIMIT $2"
-		rows, err = r.pool.Query(ctx, query, feedName, limit)
-	} else {
-		rows, err = r.pool.Query(ctx, query, feedName)
+	if filter.Num > 0 {
+		args = append(args, filter.Num)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
 
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -101,15 +396,10 @@ func (r *ArticleRepo) List(ctx context.Context, feedName string, limit int) ([]*
 
 	articles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.RSSItem, error) {
 		var item models.RSSItem
-		err := row.Scan(
-			&item.Title,
-			&item.Link,
-			&item.Description,
-			&item.PubDate,
-		)
-		if err != nil {
+		if err := row.Scan(articleFields(&item)...); err != nil {
 			return nil, err
 		}
+		item.PubDateInvalid = item.PubDate != ""
 		return &item, nil
 	})
 
@@ -119,3 +409,199 @@ func (r *ArticleRepo) List(ctx context.Context, feedName string, limit int) ([]*
 
 	return articles, nil
 }
+
+// Search fetches articles matching the web search style query, the most relevant first.
+// Snippets are fragments of the descriptions with the matched words wrapped in SearchMark.
+func (r *ArticleRepo) Search(ctx context.Context, filter models.SearchFilter) ([]*models.SearchResult, error) {
+	const op = "ArticleRepo.Search"
+
+	var (
+		conditions = []string{"a.search_vector @@ q.query", "NOT COALESCE(s.hidden, FALSE)"}
+		args       = []any{filter.Query, filter.UserID}
+	)
+
+	if filter.FeedName != "" {
+		args = append(args, filter.FeedName)
+		conditions = append(conditions, fmt.Sprintf("sub.name = $%d", len(args)))
+	}
+
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since.UTC())
+		conditions = append(conditions, fmt.Sprintf("a.published_at >= $%d", len(args)))
+	}
+
+	// Snippets are built from the descriptions converted to plain text
+	args = append(args, fmt.Sprintf("StartSel=%[1]s, StopSel=%[1]s, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=\" ... \"", models.SearchMark))
+	headline := fmt.Sprintf("ts_headline('english', regexp_replace(a.description, '<[^>]*>', ' ', 'g'), q.query, $%d)", len(args))
+
+	query := `
+		SELECT ` + articleColumns + `,
+			ts_rank_cd(a.search_vector, q.query) AS rank,
+			` + headline + `
+		FROM
+			articles a
+		JOIN feeds f ON a.feed_id = f.id
+		` + subscription(2) + `
+		` + articleStates(2) + `
+		CROSS JOIN websearch_to_tsquery('english', $1) AS q(query)
+		WHERE
+			` + strings.Join(conditions, " AND ") + `
+		ORDER BY
+			rank DESC, a.published_at DESC`
+
+	if filter.Num > 0 {
+		args = append(args, filter.Num)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.SearchResult, error) {
+		result := &models.SearchResult{Article: new(models.RSSItem)}
+		fields := append(articleFields(result.Article), &result.Rank, &result.Snippet)
+		if err := row.Scan(fields...); err != nil {
+			return nil, err
+		}
+		result.Article.PubDateInvalid = result.Article.PubDate != ""
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// MarkRead marks articles of the feed published before the time as read for the user,
+// the number of newly read articles is returned
+func (r *ArticleRepo) MarkRead(ctx context.Context, userID, feedName string, before time.Time) (int64, error) {
+	const op = "ArticleRepo.MarkRead"
+
+	query := `
+		INSERT INTO article_states(user_id, article_id, read)
+		SELECT $1, a.id, TRUE
+		FROM articles a
+		JOIN feeds f ON a.feed_id = f.id
+		` + subscription(1) + `
+		WHERE sub.name = $2 AND a.published_at < $3
+		ON CONFLICT (user_id, article_id) DO UPDATE SET
+			read = TRUE,
+			updated_at = NOW()
+		WHERE NOT article_states.read`
+
+	tag, err := r.pool.Exec(ctx, query, userID, feedName, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// SetStarred stars or unstars the article for the user, only articles of subscribed feeds are found
+func (r *ArticleRepo) SetStarred(ctx context.Context, userID, id string, starred bool) error {
+	const op = "ArticleRepo.SetStarred"
+
+	query := `
+		INSERT INTO article_states(user_id, article_id, starred)
+		SELECT $1, a.id, $3
+		FROM articles a
+		JOIN feeds f ON a.feed_id = f.id
+		` + subscription(1) + `
+		WHERE a.id = $2
+		ON CONFLICT (user_id, article_id) DO UPDATE SET
+			starred = EXCLUDED.starred,
+			updated_at = NOW()`
+
+	// Ids are passed by users, anything but a UUID can't be an article
+	if !uuidPattern.MatchString(id) {
+		return fmt.Errorf("%s: %w", op, ErrArticleNotFound)
+	}
+
+	tag, err := r.pool.Exec(ctx, query, userID, id, starred)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrArticleNotFound)
+	}
+
+	return nil
+}
+
+// SetStates adds the states set by rules to the articles, states set before are kept
+func (r *ArticleRepo) SetStates(ctx context.Context, states []models.ArticleState) error {
+	const op = "ArticleRepo.SetStates"
+
+	query := `
+		INSERT INTO article_states(user_id, article_id, read, starred, hidden, tags)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6::TEXT[], '{}'))
+		ON CONFLICT (user_id, article_id) DO UPDATE SET
+			read = article_states.read OR EXCLUDED.read,
+			starred = article_states.starred OR EXCLUDED.starred,
+			hidden = article_states.hidden OR EXCLUDED.hidden,
+			tags = ARRAY(SELECT DISTINCT t FROM UNNEST(article_states.tags || EXCLUDED.tags) t ORDER BY t),
+			updated_at = NOW()`
+
+	batch := &pgx.Batch{}
+	for _, state := range states {
+		batch.Queue(query, state.UserID, state.ArticleID, state.Read, state.Starred, state.Hidden, state.Tags)
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LoadEnclosures fills enclosures of the given articles
+func (r *ArticleRepo) LoadEnclosures(ctx context.Context, articles []*models.RSSItem) error {
+	const op = "ArticleRepo.LoadEnclosures"
+
+	query := `
+		SELECT article_id, url, COALESCE(mime_type, ''), COALESCE(length, 0), kind
+		FROM article_enclosures
+		WHERE article_id = ANY($1)
+		ORDER BY kind, created_at`
+
+	byID := make(map[string]*models.RSSItem, len(articles))
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+		ids = append(ids, article.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			articleID string
+			e         models.Enclosure
+		)
+		if err := rows.Scan(&articleID, &e.URL, &e.Type, &e.Length, &e.Kind); err != nil {
+			return fmt.Errorf("%s: scan error: %w", op, err)
+		}
+		if article, ok := byID[articleID]; ok {
+			article.Enclosures = append(article.Enclosures, e)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return nil
+}
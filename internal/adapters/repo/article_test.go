@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"slices"
+	"testing"
+)
+
+func TestRekeyCandidates(t *testing.T) {
+	// Two items of one feed without guid and link, they must not re-key each other's articles
+	articles := []models.RSSItem{
+		{GUID: "fallback-1", Title: "First note", GUIDFallback: true},
+		{GUID: "fallback-2", Title: "Second note", GUIDFallback: true},
+		{GUID: "urn:note:3", Title: "Third note", Link: "https://example.com/3"},
+	}
+
+	guids, links, titles := rekeyCandidates(articles)
+	if !slices.Equal(guids, []string{"urn:note:3"}) ||
+		!slices.Equal(links, []string{"https://example.com/3"}) ||
+		!slices.Equal(titles, []string{"Third note"}) {
+		t.Errorf("rekeyCandidates() = %q, %q, %q, want only the item with a publisher guid", guids, links, titles)
+	}
+
+	if guids, _, _ := rekeyCandidates(articles[:2]); len(guids) != 0 {
+		t.Errorf("rekeyCandidates() of items without guid = %q, want none", guids)
+	}
+}
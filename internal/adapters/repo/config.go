@@ -28,9 +28,10 @@ func (r *ConfigRepo) Get(ctx context.Context) (*models.RssConfig, error) {
 	const op = "ConfigRepo.Get"
 	const query = `
 		SELECT 
-			run, 
 			worker_count, 
-			timer_interval 
+			timer_interval,
+			host_max_concurrency,
+			host_min_spacing
 		FROM 
 			config 
 		LIMIT 1`
@@ -38,9 +39,10 @@ func (r *ConfigRepo) Get(ctx context.Context) (*models.RssConfig, error) {
 	var config models.RssConfig
 
 	err := r.pool.QueryRow(ctx, query).Scan(
-		&config.Run,
 		&config.WorkerCount,
 		&config.TimerInterval,
+		&config.HostMaxConcurrency,
+		&config.HostMinSpacing,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -58,14 +60,16 @@ func (r *ConfigRepo) Update(ctx context.Context, config *models.RssConfig) error
 	const query = `
 		UPDATE config 
 		SET 
-			run = $1,
-			worker_count = $2,
-			timer_interval = $3`
+			worker_count = $1,
+			timer_interval = $2,
+			host_max_concurrency = $3,
+			host_min_spacing = $4`
 
 	_, err := r.pool.Exec(ctx, query,
-		config.Run,
 		config.WorkerCount,
 		config.TimerInterval,
+		config.HostMaxConcurrency,
+		config.HostMinSpacing,
 	)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -74,21 +78,6 @@ func (r *ConfigRepo) Update(ctx context.Context, config *models.RssConfig) error
 	return nil
 }
 
-// UpdateRunStatus updates only the run status in the configuration
-func (r *ConfigRepo) UpdateRunStatus(ctx context.Context, run bool) error {
-	const op = "ConfigRepo.UpdateRunStatus"
-	const query = `
-		UPDATE config 
-		SET run = $1`
-
-	_, err := r.pool.Exec(ctx, query, run)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
 // UpdateWorkerCount updates only the worker count in the configuration
 func (r *ConfigRepo) UpdateWorkerCount(ctx context.Context, count int) (int, error) {
 	const op = "ConfigRepo.UpdateWorkerCount"
@@ -128,3 +117,19 @@ func (r *ConfigRepo) UpdateTimerInterval(ctx context.Context, interval time.Dura
 
 	return &lastInterval, nil
 }
+
+// UpdateHostLimits updates only the per-host limits in the configuration
+func (r *ConfigRepo) UpdateHostLimits(ctx context.Context, maxConcurrency int, minSpacing time.Duration) error {
+	const op = "ConfigRepo.UpdateHostLimits"
+	const query = `
+		UPDATE config 
+		SET host_max_concurrency = $1,
+			host_min_spacing = $2`
+
+	_, err := r.pool.Exec(ctx, query, maxConcurrency, minSpacing)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -13,6 +13,46 @@ import (
 
 var ErrFeedNotFound = errors.New("feed not found")
 
+// feedColumns are the columns read by scanFeed, name and description are the ones given by the user who added the feed
+const feedColumns = `id, name, COALESCE(description, ''), ` + feedStateColumns
+
+// subscribedFeedColumns are the columns read by scanFeed of feeds joined with subscriptions s by subscribed,
+// name and description are the ones of the subscription
+const subscribedFeedColumns = `id, s.name, s.description, ` + feedStateColumns
+
+const feedStateColumns = `url, COALESCE(format, ''), fetch_interval, created_at, updated_at,
+			COALESCE(etag, ''), COALESCE(last_modified, ''),
+			COALESCE(ttl, '0'), COALESCE(skip_hours, '{}'), COALESCE(skip_days, '{}'),
+			failure_count, COALESCE(last_error, ''), next_fetch_at, disabled`
+
+// scanFeed reads a row selected with feedColumns
+func scanFeed(row pgx.Row, feed *models.Feed) error {
+	return row.Scan(feedFields(feed)...)
+}
+
+// feedFields returns destinations for the columns of feedColumns, in the same order
+func feedFields(feed *models.Feed) []any {
+	return []any{
+		&feed.ID,
+		&feed.Name,
+		&feed.Description,
+		&feed.URL,
+		&feed.Format,
+		&feed.Interval,
+		&feed.CreatedAt,
+		&feed.UpdatedAt,
+		&feed.ETag,
+		&feed.LastModified,
+		&feed.Hints.TTL,
+		&feed.Hints.SkipHours,
+		&feed.Hints.SkipDays,
+		&feed.FailureCount,
+		&feed.LastError,
+		&feed.NextFetchAt,
+		&feed.Disabled,
+	}
+}
+
 type FeedRepo struct {
 	db *pgxpool.Pool
 }
@@ -23,20 +63,35 @@ func NewFeedRepo(pool *pgxpool.Pool) *FeedRepo {
 	}
 }
 
-// Create new feed in database.
-func (r *FeedRepo) Create(ctx context.Context, feed *models.Feed) error {
+// subscribed joins subscriptions of the user in the given parameter to feeds, as s with feed_id, name, description and subscribed_at
+func subscribed(param int) string {
+	return fmt.Sprintf(`JOIN (
+			SELECT feed_id, name, COALESCE(description, '') AS description, created_at AS subscribed_at
+			FROM subscriptions WHERE user_id = $%d
+		) s ON s.feed_id = feeds.id`, param)
+}
+
+// Create new feed in database, the user is subscribed to it under the name of the feed.
+func (r *FeedRepo) Create(ctx context.Context, userID string, feed *models.Feed) error {
 	const op = "FeedRepo.Create"
 
 	query := `
-	INSERT INTO feeds(name, description, url)
-	VALUES($1, $2, $3)
-	RETURNING id, created_at;
+	WITH feed AS (
+		INSERT INTO feeds(name, description, url)
+		VALUES($1, $2, $3)
+		RETURNING id, created_at
+	), subscription AS (
+		INSERT INTO subscriptions(user_id, feed_id, name, description)
+		SELECT $4, id, $1, $2 FROM feed
+	)
+	SELECT id, created_at FROM feed;
 	`
 
 	err := r.db.QueryRow(ctx, query,
 		feed.Name,
 		feed.Description,
 		feed.URL,
+		userID,
 	).Scan(&feed.ID, &feed.CreatedAt)
 
 	if err != nil {
@@ -46,33 +101,84 @@ func (r *FeedRepo) Create(ctx context.Context, feed *models.Feed) error {
 	return nil
 }
 
-// Delete feed by name and its articles by cascade
-func (r *FeedRepo) Delete(ctx context.Context, name string) error {
-	const op = "FeedRepo.Delete"
+// Subscribe subscribes the user to the feed under the given name and description,
+// false is returned when the user is already subscribed to the feed or has a feed with the name
+func (r *FeedRepo) Subscribe(ctx context.Context, userID, feedID, name, description string) (bool, error) {
+	const op = "FeedRepo.Subscribe"
 
 	query := `
-	DELETE FROM feeds
-	WHERE name = $1;
+	INSERT INTO subscriptions(user_id, feed_id, name, description)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT DO NOTHING;
 	`
 
-	tag, err := r.db.Exec(ctx, query, name)
+	tag, err := r.db.Exec(ctx, query, userID, feedID, name, description)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+// Delete unsubscribes the user from the feed by name,
+// the feed is deleted with its articles by cascade when no subscribers are left.
+func (r *FeedRepo) Delete(ctx context.Context, userID, name string) error {
+	const op = "FeedRepo.Delete"
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback(ctx)
 
-	if tag.RowsAffected() == 0 {
+	query := `
+	DELETE FROM subscriptions
+	WHERE user_id = $1 AND name = $2
+	RETURNING feed_id;
+	`
+
+	var feedID string
+	err = tx.QueryRow(ctx, query, userID, name).Scan(&feedID)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("%s: %w", op, ErrFeedNotFound)
 	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The feed leaves groups of the user
+	query = `
+	DELETE FROM group_feeds gf
+	USING groups g
+	WHERE gf.group_id = g.id AND g.user_id = $1 AND gf.feed_id = $2;
+	`
+
+	if _, err := tx.Exec(ctx, query, userID, feedID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `
+	DELETE FROM feeds f
+	WHERE f.id = $1 AND NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.feed_id = f.id);
+	`
+
+	if _, err := tx.Exec(ctx, query, feedID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	return nil
 }
 
-// ListAll fetches all recent feeds from the database
+// ListAll fetches all recent feeds of all users from the database
 func (r *FeedRepo) ListAll(ctx context.Context) ([]*models.Feed, error) {
 	const op = "FeedRepo.ListAll"
 
 	query := `
-		SELECT id, name, description, url, created_at, updated_at
+		SELECT ` + feedColumns + `
 		FROM feeds
 		ORDER BY created_at DESC;
 	`
@@ -85,15 +191,7 @@ func (r *FeedRepo) ListAll(ctx context.Context) ([]*models.Feed, error) {
 
 	feeds, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Feed, error) {
 		var feed models.Feed
-		err := row.Scan(
-			&feed.ID,
-			&feed.Name,
-			&feed.Description,
-			&feed.URL,
-			&feed.CreatedAt,
-			&feed.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanFeed(row, &feed); err != nil {
 			return nil, err
 		}
 		return &feed, nil
@@ -105,18 +203,34 @@ func (r *FeedRepo) ListAll(ctx context.Context) ([]*models.Feed, error) {
 	return feeds, nil
 }
 
-// List fetches up to `limit` recent feeds from the database
-func (r *FeedRepo) List(ctx context.Context, limit int) ([]*models.Feed, error) {
+// List fetches feeds the user subscribed to matching the filter, most recent subscriptions first
+func (r *FeedRepo) List(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.Feed, error) {
 	const op = "FeedRepo.List"
 
 	query := `
-		SELECT id, name, description, url, created_at, updated_at
+		SELECT ` + subscribedFeedColumns + `
 		FROM feeds
-		ORDER BY created_at DESC
-		LIMIT $1;
-	`
+		` + subscribed(1)
+
+	args := []any{userID}
+	if filter.Group != "" {
+		args = append(args, filter.Group)
+		query += fmt.Sprintf(`
+		WHERE EXISTS (
+			SELECT 1 FROM group_feeds gf
+			JOIN groups g ON gf.group_id = g.id
+			WHERE gf.feed_id = feeds.id AND g.user_id = $1 AND g.name = $%d)`, len(args))
+	}
+
+	query += `
+		ORDER BY s.subscribed_at DESC`
+
+	if filter.Num > 0 {
+		args = append(args, filter.Num)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 
-	rows, err := r.db.Query(ctx, query, limit)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -124,15 +238,7 @@ func (r *FeedRepo) List(ctx context.Context, limit int) ([]*models.Feed, error)
 
 	feeds, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Feed, error) {
 		var feed models.Feed
-		err := row.Scan(
-			&feed.ID,
-			&feed.Name,
-			&feed.Description,
-			&feed.URL,
-			&feed.CreatedAt,
-			&feed.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanFeed(row, &feed); err != nil {
 			return nil, err
 		}
 		return &feed, nil
@@ -144,36 +250,107 @@ func (r *FeedRepo) List(ctx context.Context, limit int) ([]*models.Feed, error)
 	return feeds, nil
 }
 
-// Exist
-func (f *FeedRepo) Exist(ctx context.Context, name string) (bool, error) {
-	const op = "FeedRepo.IsUnique"
+// Get fetches the feed the user is subscribed to by name
+func (r *FeedRepo) Get(ctx context.Context, userID, name string) (*models.Feed, error) {
+	const op = "FeedRepo.Get"
+
+	query := `
+		SELECT ` + subscribedFeedColumns + `
+		FROM feeds
+		` + subscribed(1) + `
+		WHERE s.name = $2;
+	`
+
+	var feed models.Feed
+	err := scanFeed(r.db.QueryRow(ctx, query, userID, name), &feed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrFeedNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &feed, nil
+}
+
+// GetByURL fetches the feed by URL regardless of its subscribers
+func (r *FeedRepo) GetByURL(ctx context.Context, url string) (*models.Feed, error) {
+	const op = "FeedRepo.GetByURL"
+
+	query := `
+		SELECT ` + feedColumns + `
+		FROM feeds
+		WHERE url = $1
+		ORDER BY created_at
+		LIMIT 1;
+	`
+
+	var feed models.Feed
+	err := scanFeed(r.db.QueryRow(ctx, query, url), &feed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrFeedNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &feed, nil
+}
+
+// Subscribed reports whether the user is subscribed to a feed with the name
+func (f *FeedRepo) Subscribed(ctx context.Context, userID, name string) (bool, error) {
+	const op = "FeedRepo.Subscribed"
 
 	query := `
-	SELECT COUNT(*) != 0  as exist
-	FROM feeds
-	WHERE Name = $1
+	SELECT EXISTS (
+		SELECT 1 FROM subscriptions
+		WHERE user_id = $1 AND name = $2
+	)
 	`
-	var exist bool
-	if err := f.db.QueryRow(ctx, query, name).
-		Scan(&exist); err != nil {
+	var subscribed bool
+	if err := f.db.QueryRow(ctx, query, userID, name).Scan(&subscribed); err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return exist, nil
+	return subscribed, nil
 }
 
-// GetStaleFeeds returns all feeds that haven't been updated in the given period.
-func (f *FeedRepo) GetStaleFeeds(ctx context.Context, period time.Duration) ([]*models.Feed, error) {
+// CountSubscribers returns the number of users subscribed to the feed
+func (f *FeedRepo) CountSubscribers(ctx context.Context, feedID string) (int, error) {
+	const op = "FeedRepo.CountSubscribers"
+
+	var count int
+	if err := f.db.QueryRow(ctx, `SELECT COUNT(*) FROM subscriptions WHERE feed_id = $1`, feedID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// GetStaleFeeds claims and returns feeds that haven't been updated within their own fetch interval,
+// period is used for feeds without one. Publisher hints (ttl, skip hours and days) and failure backoff hold feeds back,
+// disabled feeds are never returned. Returned feeds are leased to the instance for the lease duration,
+// feeds leased by other instances are skipped, so concurrent aggregators partition the work.
+func (f *FeedRepo) GetStaleFeeds(ctx context.Context, period time.Duration, instanceID string, lease time.Duration) ([]*models.Feed, error) {
 	const op = "FeedRepo.GetStaleFeeds"
 
 	query := `
-		SELECT id, name, description, url, created_at, updated_at
-		FROM feeds
-		WHERE updated_at IS NULL OR updated_at < $1
+		UPDATE feeds
+		SET claimed_by = $2,
+			lease_expires_at = NOW() + $3::INTERVAL
+		WHERE id IN (
+			SELECT id
+			FROM feeds
+			WHERE (updated_at IS NULL OR updated_at < NOW() - GREATEST(COALESCE(fetch_interval, $1), COALESCE(ttl, '0')))
+				AND NOT ` + skippedNow + `
+				AND ` + fetchAllowed + `
+				AND (claimed_by = $2 OR ` + leaseFree + `)
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + feedColumns + `
 	`
 
-	cutoff := time.Now().Add(-period)
-	rows, err := f.db.Query(ctx, query, cutoff)
+	rows, err := f.db.Query(ctx, query, period, instanceID, lease)
 	if err != nil {
 		return nil, fmt.Errorf("%s: query error: %w", op, err)
 	}
@@ -182,15 +359,7 @@ func (f *FeedRepo) GetStaleFeeds(ctx context.Context, period time.Duration) ([]*
 	var feeds []*models.Feed
 	for rows.Next() {
 		feed := new(models.Feed)
-		err := rows.Scan(
-			&feed.ID,
-			&feed.Name,
-			&feed.Description,
-			&feed.URL,
-			&feed.CreatedAt,
-			&feed.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanFeed(rows, feed); err != nil {
 			return nil, fmt.Errorf("%s: scan error: %w", op, err)
 		}
 		feeds = append(feeds, feed)
@@ -204,16 +373,249 @@ func (f *FeedRepo) GetStaleFeeds(ctx context.Context, period time.Duration) ([]*
 }
 
 // UpdateUpdatedAt updates updated_at field
-func (f *FeedRepo) UpdateUpdatedAt(ctx context.Context, name string) error {
+func (f *FeedRepo) UpdateUpdatedAt(ctx context.Context, id string) error {
 	const op = "FeedRepo.UpdateUpdatedAt"
 
 	query := `
 		UPDATE feeds 
 		SET updated_at = NOW()
-		WHERE name = $1;
+		WHERE id = $1;
+	`
+
+	_, err := f.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateFormat stores the document format detected on the last fetch
+func (f *FeedRepo) UpdateFormat(ctx context.Context, id, format string) error {
+	const op = "FeedRepo.UpdateFormat"
+
+	query := `
+		UPDATE feeds 
+		SET format = $2
+		WHERE id = $1;
+	`
+
+	_, err := f.db.Exec(ctx, query, id, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateValidators stores the ETag and Last-Modified values of the last successful fetch
+func (f *FeedRepo) UpdateValidators(ctx context.Context, id, etag, lastModified string) error {
+	const op = "FeedRepo.UpdateValidators"
+
+	query := `
+		UPDATE feeds 
+		SET etag = NULLIF($2, ''),
+			last_modified = NULLIF($3, '')
+		WHERE id = $1;
+	`
+
+	_, err := f.db.Exec(ctx, query, id, etag, lastModified)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// skippedNow is true for feeds whose publisher asks not to be fetched at the current GMT hour or week day
+const skippedNow = `(
+			EXTRACT(HOUR FROM NOW() AT TIME ZONE 'UTC')::INT = ANY(COALESCE(skip_hours, '{}'))
+			OR to_char(NOW() AT TIME ZONE 'UTC', 'FMDay') = ANY(COALESCE(skip_days, '{}'))
+		)`
+
+// fetchAllowed is false for disabled feeds and failing feeds waiting for their backoff to pass
+const fetchAllowed = `(NOT disabled AND (next_fetch_at IS NULL OR next_fetch_at <= NOW()))`
+
+// leaseFree is true for feeds not leased by any live instance
+const leaseFree = `(claimed_by IS NULL OR lease_expires_at IS NULL OR lease_expires_at < NOW())`
+
+// RenewLeases extends leases of the instance on feeds with a pending or running fetch job,
+// leases of other feeds are left to expire
+func (f *FeedRepo) RenewLeases(ctx context.Context, instanceID string, lease time.Duration) error {
+	const op = "FeedRepo.RenewLeases"
+
+	query := `
+		UPDATE feeds
+		SET lease_expires_at = NOW() + $2::INTERVAL
+		WHERE claimed_by = $1
+			AND EXISTS (
+				SELECT 1 FROM fetch_jobs j
+				WHERE j.feed_id = feeds.id AND j.status IN ('pending', 'running')
+			)
+	`
+
+	if _, err := f.db.Exec(ctx, query, instanceID, lease); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseLease frees the lease of the instance on the feed
+func (f *FeedRepo) ReleaseLease(ctx context.Context, id, instanceID string) error {
+	const op = "FeedRepo.ReleaseLease"
+
+	query := `
+		UPDATE feeds
+		SET claimed_by = NULL,
+			lease_expires_at = NULL
+		WHERE id = $1 AND claimed_by = $2
+	`
+
+	if _, err := f.db.Exec(ctx, query, id, instanceID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountHeldBack returns how many feeds are due by their fetch interval but held back by publisher hints
+func (f *FeedRepo) CountHeldBack(ctx context.Context, period time.Duration) (int, error) {
+	const op = "FeedRepo.CountHeldBack"
+
+	query := `
+		SELECT COUNT(*)
+		FROM feeds
+		WHERE (updated_at IS NULL OR updated_at < NOW() - COALESCE(fetch_interval, $1))
+			AND (updated_at >= NOW() - COALESCE(ttl, '0') OR ` + skippedNow + `)
+			AND ` + fetchAllowed + `
+	`
+
+	var count int
+	if err := f.db.QueryRow(ctx, query, period).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// UpdateHints stores publisher hints announced by the feed
+func (f *FeedRepo) UpdateHints(ctx context.Context, id string, hints models.PublisherHints) error {
+	const op = "FeedRepo.UpdateHints"
+
+	query := `
+		UPDATE feeds 
+		SET ttl = NULLIF($2::INTERVAL, '0'),
+			skip_hours = $3,
+			skip_days = $4
+		WHERE id = $1;
+	`
+
+	_, err := f.db.Exec(ctx, query, id, hints.TTL, hints.SkipHours, hints.SkipDays)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateInterval sets own fetch interval of the feed, nil resets it to the global one
+func (f *FeedRepo) UpdateInterval(ctx context.Context, id string, interval *time.Duration) error {
+	const op = "FeedRepo.UpdateInterval"
+
+	query := `
+		UPDATE feeds 
+		SET fetch_interval = $2
+		WHERE id = $1;
+	`
+
+	tag, err := f.db.Exec(ctx, query, id, interval)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrFeedNotFound)
+	}
+
+	return nil
+}
+
+// MinInterval returns the shortest own fetch interval among feeds, nil when no feed has one
+func (f *FeedRepo) MinInterval(ctx context.Context) (*time.Duration, error) {
+	const op = "FeedRepo.MinInterval"
+
+	query := `
+		SELECT MIN(fetch_interval)
+		FROM feeds;
+	`
+
+	var interval *time.Duration
+	if err := f.db.QueryRow(ctx, query).Scan(&interval); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return interval, nil
+}
+
+// RecordFailure stores the fetch error and postpones the next fetch by base * 2^(failures - 1), limited by maxBackoff.
+// The feed is disabled when it has failed maxFailures times in a row, the time of the next fetch and the new disabled state are returned.
+func (f *FeedRepo) RecordFailure(ctx context.Context, id, lastError string, base, maxBackoff time.Duration, maxFailures int) (time.Time, bool, error) {
+	const op = "FeedRepo.RecordFailure"
+
+	query := `
+		UPDATE feeds 
+		SET failure_count = failure_count + 1,
+			last_error = $2,
+			next_fetch_at = NOW() + LEAST($3::INTERVAL * POWER(2, LEAST(failure_count, 30)), $4::INTERVAL),
+			disabled = failure_count + 1 >= $5
+		WHERE id = $1
+		RETURNING next_fetch_at, disabled;
+	`
+
+	var (
+		nextFetchAt time.Time
+		disabled    bool
+	)
+	if err := f.db.QueryRow(ctx, query, id, lastError, base, maxBackoff, maxFailures).Scan(&nextFetchAt, &disabled); err != nil {
+		return time.Time{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nextFetchAt, disabled, nil
+}
+
+// ResetFailures clears the failure state of the feed and enables it
+func (f *FeedRepo) ResetFailures(ctx context.Context, id string) error {
+	const op = "FeedRepo.ResetFailures"
+
+	query := `
+		UPDATE feeds 
+		SET failure_count = 0,
+			last_error = NULL,
+			next_fetch_at = NULL,
+			disabled = FALSE
+		WHERE id = $1;
+	`
+
+	_, err := f.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PostponeFetch moves the next fetch of the feed to the given time, an already later one is kept
+func (f *FeedRepo) PostponeFetch(ctx context.Context, id string, until time.Time) error {
+	const op = "FeedRepo.PostponeFetch"
+
+	query := `
+		UPDATE feeds 
+		SET next_fetch_at = GREATEST(next_fetch_at, $2)
+		WHERE id = $1;
 	`
 
-	_, err := f.db.Exec(ctx, query, name)
+	_, err := f.db.Exec(ctx, query, id, until.UTC())
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -0,0 +1,192 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupExists   = errors.New("group already exists")
+)
+
+type GroupRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewGroupRepo(pool *pgxpool.Pool) *GroupRepo {
+	return &GroupRepo{
+		pool: pool,
+	}
+}
+
+// Get fetches the group of the user by name
+func (r *GroupRepo) Get(ctx context.Context, userID, name string) (*models.Group, error) {
+	const op = "GroupRepo.Get"
+
+	query := `
+		SELECT id, name, created_at
+		FROM groups
+		WHERE user_id = $1 AND name = $2
+	`
+
+	var group models.Group
+	err := r.pool.QueryRow(ctx, query, userID, name).Scan(&group.ID, &group.Name, &group.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &group, nil
+}
+
+// Create creates a new group of the user
+func (r *GroupRepo) Create(ctx context.Context, userID, name string) (*models.Group, error) {
+	const op = "GroupRepo.Create"
+
+	query := `
+		INSERT INTO groups(user_id, name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING id, name, created_at
+	`
+
+	var group models.Group
+	err := r.pool.QueryRow(ctx, query, userID, name).Scan(&group.ID, &group.Name, &group.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrGroupExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &group, nil
+}
+
+// AddFeed adds the feed to the group of the user, the user must be subscribed to the feed.
+// Adding a member feed again is not an error.
+func (r *GroupRepo) AddFeed(ctx context.Context, userID, groupName, feedName string) error {
+	const op = "GroupRepo.AddFeed"
+
+	query := `
+		WITH g AS (
+			SELECT id FROM groups WHERE user_id = $1 AND name = $2
+		), f AS (
+			SELECT feed_id AS id FROM subscriptions
+			WHERE user_id = $1 AND name = $3
+		), added AS (
+			INSERT INTO group_feeds(group_id, feed_id)
+			SELECT g.id, f.id FROM g, f
+			ON CONFLICT DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM g), EXISTS (SELECT 1 FROM f)
+	`
+
+	var groupExists, feedExists bool
+	if err := r.pool.QueryRow(ctx, query, userID, groupName, feedName).Scan(&groupExists, &feedExists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case !groupExists:
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	case !feedExists:
+		return fmt.Errorf("%s: %w", op, ErrFeedNotFound)
+	}
+
+	return nil
+}
+
+// RemoveFeed removes the feed from the group of the user, the feed itself is kept
+func (r *GroupRepo) RemoveFeed(ctx context.Context, userID, groupName, feedName string) error {
+	const op = "GroupRepo.RemoveFeed"
+
+	query := `
+		WITH g AS (
+			SELECT id FROM groups WHERE user_id = $1 AND name = $2
+		), removed AS (
+			DELETE FROM group_feeds gf
+			USING g, subscriptions s
+			WHERE gf.group_id = g.id AND gf.feed_id = s.feed_id AND s.user_id = $1 AND s.name = $3
+			RETURNING gf.feed_id
+		)
+		SELECT EXISTS (SELECT 1 FROM g), EXISTS (SELECT 1 FROM removed)
+	`
+
+	var groupExists, removed bool
+	if err := r.pool.QueryRow(ctx, query, userID, groupName, feedName).Scan(&groupExists, &removed); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case !groupExists:
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	case !removed:
+		return fmt.Errorf("%s: %w", op, ErrFeedNotFound)
+	}
+
+	return nil
+}
+
+// Delete deletes the group of the user, its feeds are kept
+func (r *GroupRepo) Delete(ctx context.Context, userID, name string) error {
+	const op = "GroupRepo.Delete"
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM groups WHERE user_id = $1 AND name = $2`, userID, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+// List fetches all groups of the user ordered by name with the names of their feeds
+func (r *GroupRepo) List(ctx context.Context, userID string) ([]*models.Group, error) {
+	const op = "GroupRepo.List"
+
+	query := `
+		SELECT
+			g.id,
+			g.name,
+			g.created_at,
+			ARRAY(
+				SELECT s.name FROM group_feeds gf
+				JOIN subscriptions s ON s.feed_id = gf.feed_id AND s.user_id = g.user_id
+				WHERE gf.group_id = g.id
+				ORDER BY s.name
+			)
+		FROM groups g
+		WHERE g.user_id = $1
+		ORDER BY g.name
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	groups, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Group, error) {
+		var group models.Group
+		if err := row.Scan(&group.ID, &group.Name, &group.CreatedAt, &group.FeedNames); err != nil {
+			return nil, err
+		}
+		return &group, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// instanceLockSpace is the first key of advisory locks held by running instances,
+// the second key is the hash of the instance id
+const instanceLockSpace int32 = 0x72737368 // "rssh"
+
+// instanceLocked is true for instances whose session still holds their advisory lock
+var instanceLocked = fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM pg_locks l
+			WHERE l.locktype = 'advisory'
+				AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+				AND l.classid = %d::OID
+				AND l.objid = hashtext(i.id::TEXT)::OID
+				AND l.objsubid = 2
+				AND l.granted
+		)`, instanceLockSpace)
+
+var ErrLockHeld = errors.New("advisory lock of the instance is held by another session")
+
+// AdvisoryLock is a session-level advisory lock, it lives as long as its dedicated connection,
+// so the lock of a killed process is released by Postgres when the connection drops.
+type AdvisoryLock struct {
+	conn *pgx.Conn
+	id   string
+}
+
+// ID returns the id of the instance holding the lock
+func (l *AdvisoryLock) ID() string {
+	return l.id
+}
+
+// Alive checks that the session holding the lock is still connected
+func (l *AdvisoryLock) Alive(ctx context.Context) error {
+	return l.conn.Ping(ctx)
+}
+
+// Release unlocks the lock and closes its connection
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	const op = "AdvisoryLock.Release"
+
+	// Closing the session releases the lock even when the unlock fails
+	defer l.conn.Close(ctx)
+
+	if _, err := l.conn.Exec(ctx, `SELECT pg_advisory_unlock($1, hashtext($2))`, instanceLockSpace, l.id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type InstanceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewInstanceRepo(pool *pgxpool.Pool) *InstanceRepo {
+	return &InstanceRepo{
+		pool: pool,
+	}
+}
+
+// Lock generates an id for a new instance and takes its advisory lock on a connection detached from the pool,
+// the instance is alive for other instances as long as the lock is held.
+func (r *InstanceRepo) Lock(ctx context.Context) (*AdvisoryLock, error) {
+	const op = "InstanceRepo.Lock"
+
+	query := `
+		SELECT id, pg_try_advisory_lock($1, hashtext(id::TEXT))
+		FROM (SELECT gen_random_uuid() AS id) AS instance
+	`
+
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	conn := pooled.Hijack()
+
+	var (
+		id     string
+		locked bool
+	)
+	if err := conn.QueryRow(ctx, query, instanceLockSpace).Scan(&id, &locked); err != nil {
+		conn.Close(ctx)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !locked {
+		conn.Close(ctx)
+		return nil, ErrLockHeld
+	}
+
+	return &AdvisoryLock{conn: conn, id: id}, nil
+}
+
+// Register creates the heartbeat row of the instance
+func (r *InstanceRepo) Register(ctx context.Context, id, host string, pid int) error {
+	const op = "InstanceRepo.Register"
+
+	query := `
+		INSERT INTO instances(id, host, pid)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, host, pid); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Heartbeat updates last_seen of the instance
+func (r *InstanceRepo) Heartbeat(ctx context.Context, id string) error {
+	const op = "InstanceRepo.Heartbeat"
+
+	query := `
+		UPDATE instances
+		SET last_seen = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: instance %s is not registered", op, id)
+	}
+
+	return nil
+}
+
+// Unregister deletes the heartbeat row of the instance
+func (r *InstanceRepo) Unregister(ctx context.Context, id string) error {
+	const op = "InstanceRepo.Unregister"
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM instances WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteStale deletes instances without a heartbeat for longer than staleAfter or without their lock,
+// their feed leases are released
+func (r *InstanceRepo) DeleteStale(ctx context.Context, staleAfter time.Duration) error {
+	const op = "InstanceRepo.DeleteStale"
+
+	query := `
+		DELETE FROM instances i
+		WHERE i.last_seen < NOW() - $1::INTERVAL OR NOT ` + instanceLocked + `
+	`
+
+	if _, err := r.pool.Exec(ctx, query, staleAfter); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListAlive returns instances holding their lock with a heartbeat within staleAfter, the oldest first,
+// with the number of their live feed leases
+func (r *InstanceRepo) ListAlive(ctx context.Context, staleAfter time.Duration) ([]*models.Instance, error) {
+	const op = "InstanceRepo.ListAlive"
+
+	query := `
+		SELECT
+			i.id,
+			i.host,
+			i.pid,
+			i.started_at,
+			i.last_seen,
+			(
+				SELECT COUNT(*) FROM feeds f
+				WHERE f.claimed_by = i.id AND f.lease_expires_at >= NOW()
+			)
+		FROM instances i
+		WHERE i.last_seen >= NOW() - $1::INTERVAL AND ` + instanceLocked + `
+		ORDER BY i.started_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, staleAfter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	instances, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Instance, error) {
+		var instance models.Instance
+		if err := row.Scan(&instance.ID, &instance.Host, &instance.PID, &instance.StartedAt, &instance.LastSeen, &instance.Leases); err != nil {
+			return nil, err
+		}
+		return &instance, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,338 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewJobRepo(pool *pgxpool.Pool) *JobRepo {
+	return &JobRepo{
+		pool: pool,
+	}
+}
+
+// Enqueue creates pending fetch jobs for the feeds, feeds which already have a pending or running job are skipped.
+// The number of created jobs is returned.
+func (r *JobRepo) Enqueue(ctx context.Context, feedIDs []string, maxAttempts int) (int64, error) {
+	const op = "JobRepo.Enqueue"
+
+	query := `
+		INSERT INTO fetch_jobs(feed_id, max_attempts)
+		SELECT feed_id, $2::INT FROM UNNEST($1::UUID[]) AS feed_id
+		ON CONFLICT (feed_id) WHERE status IN ('pending', 'running') DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, feedIDs, maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Claim marks up to limit due pending jobs as running and returns them with their feeds,
+// feeds of the jobs are leased to the instance. Jobs locked by a concurrent claim
+// and jobs of feeds leased by other instances are skipped.
+func (r *JobRepo) Claim(ctx context.Context, limit int, instanceID string, lease time.Duration) ([]*models.FetchJob, error) {
+	const op = "JobRepo.Claim"
+
+	query := `
+		WITH claimed AS (
+			UPDATE fetch_jobs
+			SET status = 'running',
+				attempts = attempts + 1,
+				locked_at = NOW(),
+				updated_at = NOW()
+			WHERE id IN (
+				SELECT j.id FROM fetch_jobs j
+				JOIN feeds f ON f.id = j.feed_id
+				WHERE j.status = 'pending' AND j.run_at <= NOW()
+					AND (f.claimed_by = $2 OR f.claimed_by IS NULL OR f.lease_expires_at IS NULL OR f.lease_expires_at < NOW())
+				ORDER BY j.run_at
+				LIMIT $1
+				FOR UPDATE OF j, f SKIP LOCKED
+			)
+			RETURNING id AS job_id, feed_id, attempts AS job_attempts, max_attempts AS job_max_attempts
+		), leased AS (
+			UPDATE feeds
+			SET claimed_by = $2,
+				lease_expires_at = NOW() + $3::INTERVAL
+			FROM claimed
+			WHERE feeds.id = claimed.feed_id
+			RETURNING feeds.*
+		)
+		SELECT job_id, job_attempts, job_max_attempts, ` + feedColumns + `
+		FROM claimed
+		JOIN leased ON leased.id = claimed.feed_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, instanceID, lease)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	defer rows.Close()
+
+	var jobs []*models.FetchJob
+	for rows.Next() {
+		job := &models.FetchJob{Status: models.JobRunning, Feed: new(models.Feed)}
+		fields := append([]any{&job.ID, &job.Attempts, &job.MaxAttempts}, feedFields(job.Feed)...)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("%s: scan error: %w", op, err)
+		}
+		job.FeedID = job.Feed.ID
+		job.FeedName = job.Feed.Name
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+// Complete marks the job as done
+func (r *JobRepo) Complete(ctx context.Context, id string) error {
+	const op = "JobRepo.Complete"
+
+	query := `
+		UPDATE fetch_jobs
+		SET status = 'done',
+			locked_at = NULL,
+			last_error = NULL,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Retry stores the error of the failed attempt and schedules the job to run again at runAt.
+// The job becomes dead when all its attempts are used, the new dead state is returned.
+func (r *JobRepo) Retry(ctx context.Context, id, lastError string, runAt time.Time) (bool, error) {
+	const op = "JobRepo.Retry"
+
+	query := `
+		UPDATE fetch_jobs
+		SET status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'pending' END,
+			run_at = $3,
+			locked_at = NULL,
+			last_error = $2,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING status = 'dead'
+	`
+
+	var dead bool
+	if err := r.pool.QueryRow(ctx, query, id, lastError, runAt.UTC()).Scan(&dead); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dead, nil
+}
+
+// Postpone returns the job to the queue until runAt without using up an attempt
+func (r *JobRepo) Postpone(ctx context.Context, id string, runAt time.Time) error {
+	const op = "JobRepo.Postpone"
+
+	query := `
+		UPDATE fetch_jobs
+		SET status = 'pending',
+			attempts = GREATEST(attempts - 1, 0),
+			run_at = $2,
+			locked_at = NULL,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, runAt.UTC()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Bury moves the job to the dead state regardless of its remaining attempts
+func (r *JobRepo) Bury(ctx context.Context, id, lastError string) error {
+	const op = "JobRepo.Bury"
+
+	query := `
+		UPDATE fetch_jobs
+		SET status = 'dead',
+			locked_at = NULL,
+			last_error = $2,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, lastError); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RequeueStuck returns jobs running longer than timeout or whose feed lease has expired to the queue,
+// e.g. after a crash of the instance running them. Stuck jobs without remaining attempts become dead.
+// The number of affected jobs is returned.
+func (r *JobRepo) RequeueStuck(ctx context.Context, timeout time.Duration) (int64, error) {
+	const op = "JobRepo.RequeueStuck"
+
+	query := `
+		UPDATE fetch_jobs
+		SET status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'pending' END,
+			run_at = NOW(),
+			locked_at = NULL,
+			last_error = 'job was not finished in time',
+			updated_at = NOW()
+		WHERE status = 'running'
+			AND (
+				locked_at < NOW() - $1::INTERVAL
+				OR NOT EXISTS (
+					SELECT 1 FROM feeds f
+					WHERE f.id = fetch_jobs.feed_id AND f.claimed_by IS NOT NULL AND f.lease_expires_at >= NOW()
+				)
+			)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// PurgeDone deletes done jobs finished before the given age
+func (r *JobRepo) PurgeDone(ctx context.Context, age time.Duration) error {
+	const op = "JobRepo.PurgeDone"
+
+	query := `
+		DELETE FROM fetch_jobs
+		WHERE status = 'done' AND updated_at < NOW() - $1::INTERVAL
+	`
+
+	if _, err := r.pool.Exec(ctx, query, age); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountByStatus returns the number of jobs in every status
+func (r *JobRepo) CountByStatus(ctx context.Context) (map[string]int, error) {
+	const op = "JobRepo.CountByStatus"
+
+	query := `SELECT status, COUNT(*) FROM fetch_jobs GROUP BY status`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query error: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%s: scan error: %w", op, err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// List fetches jobs matching the filter, the most recently changed first
+func (r *JobRepo) List(ctx context.Context, filter models.JobFilter) ([]*models.FetchJob, error) {
+	const op = "JobRepo.List"
+
+	var (
+		conditions = []string{"TRUE"}
+		args       []any
+	)
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("j.status = $%d", len(args)))
+	}
+
+	query := `
+		SELECT
+			j.id,
+			j.feed_id,
+			f.name,
+			j.status,
+			j.attempts,
+			j.max_attempts,
+			j.run_at,
+			j.locked_at,
+			COALESCE(j.last_error, ''),
+			j.created_at,
+			j.updated_at
+		FROM
+			fetch_jobs j
+		JOIN feeds f ON j.feed_id = f.id
+		WHERE
+			` + strings.Join(conditions, " AND ") + `
+		ORDER BY
+			j.updated_at DESC`
+
+	if filter.Num > 0 {
+		args = append(args, filter.Num)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	jobs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.FetchJob, error) {
+		var job models.FetchJob
+		err := row.Scan(
+			&job.ID,
+			&job.FeedID,
+			&job.FeedName,
+			&job.Status,
+			&job.Attempts,
+			&job.MaxAttempts,
+			&job.RunAt,
+			&job.LockedAt,
+			&job.LastError,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return &job, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
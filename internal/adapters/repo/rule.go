@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrRuleNotFound = errors.New("rule not found")
+
+// ruleColumns are the columns of rules r left joined with subscriptions f and groups g of their scope, read by ruleFields
+const ruleColumns = `r.id, r.user_id, COALESCE(f.name, ''), COALESCE(g.name, ''),
+			r.field, r.match, r.pattern, r.action, COALESCE(r.tag, ''), r.created_at`
+
+// ruleScope left joins subscriptions f of the rule owners to feeds and groups g the rules r are scoped to
+const ruleScope = `LEFT JOIN subscriptions f ON f.feed_id = r.feed_id AND f.user_id = r.user_id
+		LEFT JOIN groups g ON r.group_id = g.id`
+
+func ruleFields(rule *models.Rule) []any {
+	return []any{
+		&rule.ID,
+		&rule.UserID,
+		&rule.FeedName,
+		&rule.Group,
+		&rule.Field,
+		&rule.Match,
+		&rule.Pattern,
+		&rule.Action,
+		&rule.Tag,
+		&rule.CreatedAt,
+	}
+}
+
+type RuleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRuleRepo(pool *pgxpool.Pool) *RuleRepo {
+	return &RuleRepo{
+		pool: pool,
+	}
+}
+
+// Create creates the rule of its user scoped to the feed or group with the given id, empty ids mean no scope
+func (r *RuleRepo) Create(ctx context.Context, rule *models.Rule, feedID, groupID string) error {
+	const op = "RuleRepo.Create"
+
+	query := `
+		INSERT INTO rules(user_id, feed_id, group_id, field, match, pattern, action, tag)
+		VALUES ($1, NULLIF($2, '')::UUID, NULLIF($3, '')::UUID, $4, $5, $6, $7, NULLIF($8, ''))
+		RETURNING id, created_at`
+
+	err := r.pool.QueryRow(ctx, query,
+		rule.UserID,
+		feedID,
+		groupID,
+		rule.Field,
+		rule.Match,
+		rule.Pattern,
+		rule.Action,
+		rule.Tag,
+	).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Get fetches the rule of the user by id
+func (r *RuleRepo) Get(ctx context.Context, userID, id string) (*models.Rule, error) {
+	const op = "RuleRepo.Get"
+
+	// Ids are passed by users, anything but a UUID can't be a rule
+	if !uuidPattern.MatchString(id) {
+		return nil, fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+	}
+
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM rules r
+		` + ruleScope + `
+		WHERE r.user_id = $1 AND r.id = $2`
+
+	var rule models.Rule
+	err := r.pool.QueryRow(ctx, query, userID, id).Scan(ruleFields(&rule)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &rule, nil
+}
+
+// List fetches rules of the user in the order they were created
+func (r *RuleRepo) List(ctx context.Context, userID string) ([]*models.Rule, error) {
+	const op = "RuleRepo.List"
+
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM rules r
+		` + ruleScope + `
+		WHERE r.user_id = $1
+		ORDER BY r.created_at`
+
+	return r.list(ctx, op, query, userID)
+}
+
+// ListForFeed fetches rules of the feed subscribers which apply to the feed:
+// rules scoped to the feed, to groups of the owner containing it and rules without a scope
+func (r *RuleRepo) ListForFeed(ctx context.Context, feedID string) ([]*models.Rule, error) {
+	const op = "RuleRepo.ListForFeed"
+
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM rules r
+		` + ruleScope + `
+		JOIN subscriptions s ON s.user_id = r.user_id AND s.feed_id = $1
+		WHERE r.feed_id = $1
+			OR EXISTS (SELECT 1 FROM group_feeds gf WHERE gf.group_id = r.group_id AND gf.feed_id = $1)
+			OR (r.feed_id IS NULL AND r.group_id IS NULL)
+		ORDER BY r.created_at`
+
+	return r.list(ctx, op, query, feedID)
+}
+
+func (r *RuleRepo) list(ctx context.Context, op, query string, args ...any) ([]*models.Rule, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Rule, error) {
+		var rule models.Rule
+		if err := row.Scan(ruleFields(&rule)...); err != nil {
+			return nil, err
+		}
+		return &rule, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// Delete deletes the rule of the user by id
+func (r *RuleRepo) Delete(ctx context.Context, userID, id string) error {
+	const op = "RuleRepo.Delete"
+
+	if !uuidPattern.MatchString(id) {
+		return fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM rules WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+	}
+
+	return nil
+}
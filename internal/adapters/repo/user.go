@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+)
+
+// userColumns are the columns of users u read by userFields
+const userColumns = `u.id, u.name, u.token_hash IS NOT NULL, u.admin, u.created_at,
+			(SELECT COUNT(*) FROM subscriptions s WHERE s.user_id = u.id)`
+
+func userFields(user *models.User) []any {
+	return []any{&user.ID, &user.Name, &user.HasToken, &user.Admin, &user.CreatedAt, &user.Feeds}
+}
+
+type UserRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
+	return &UserRepo{
+		pool: pool,
+	}
+}
+
+// Create creates a new user with the hash of its API token
+func (r *UserRepo) Create(ctx context.Context, name, tokenHash string) (*models.User, error) {
+	const op = "UserRepo.Create"
+
+	query := `
+		INSERT INTO users(name, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING id, name, created_at
+	`
+
+	user := &models.User{HasToken: true}
+	err := r.pool.QueryRow(ctx, query, name, tokenHash).Scan(&user.ID, &user.Name, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrUserExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// Get fetches the user by name
+func (r *UserRepo) Get(ctx context.Context, name string) (*models.User, error) {
+	const op = "UserRepo.Get"
+
+	query := `SELECT ` + userColumns + ` FROM users u WHERE u.name = $1`
+
+	var user models.User
+	err := r.pool.QueryRow(ctx, query, name).Scan(userFields(&user)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &user, nil
+}
+
+// GetByToken fetches the user owning the API token with the hash
+func (r *UserRepo) GetByToken(ctx context.Context, tokenHash string) (*models.User, error) {
+	const op = "UserRepo.GetByToken"
+
+	query := `SELECT ` + userColumns + ` FROM users u WHERE u.token_hash = $1`
+
+	var user models.User
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(userFields(&user)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &user, nil
+}
+
+// SetToken replaces the API token of the user, the old token stops working
+func (r *UserRepo) SetToken(ctx context.Context, name, tokenHash string) error {
+	const op = "UserRepo.SetToken"
+
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET token_hash = $2 WHERE name = $1`, name, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// SetAdmin grants or revokes admin rights of the user
+func (r *UserRepo) SetAdmin(ctx context.Context, name string, admin bool) error {
+	const op = "UserRepo.SetAdmin"
+
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET admin = $2 WHERE name = $1`, name, admin)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// List fetches all users ordered by name
+func (r *UserRepo) List(ctx context.Context) ([]*models.User, error) {
+	const op = "UserRepo.List"
+
+	query := `SELECT ` + userColumns + ` FROM users u ORDER BY u.name`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.User, error) {
+		var user models.User
+		if err := row.Scan(userFields(&user)...); err != nil {
+			return nil, err
+		}
+		return &user, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// Delete deletes the user with its subscriptions, article states and groups.
+// Feeds left without subscribers are deleted, so they are not fetched anymore.
+func (r *UserRepo) Delete(ctx context.Context, name string) error {
+	const op = "UserRepo.Delete"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM users WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM feeds f WHERE NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.feed_id = f.id)`); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
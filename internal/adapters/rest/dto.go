@@ -0,0 +1,290 @@
+package rest
+
+import (
+	"RSSHub/internal/domain/models"
+	"time"
+)
+
+type (
+	feedResponse struct {
+		ID           string     `json:"id"`
+		Name         string     `json:"name"`
+		Description  string     `json:"description"`
+		URL          string     `json:"url"`
+		Format       string     `json:"format,omitempty"`
+		Interval     string     `json:"interval,omitempty"` // Own fetch interval, empty when the global one is used
+		CreatedAt    time.Time  `json:"created_at"`
+		UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+		FailureCount int        `json:"failure_count"`
+		LastError    string     `json:"last_error,omitempty"`
+		NextFetchAt  *time.Time `json:"next_fetch_at,omitempty"`
+		Disabled     bool       `json:"disabled"`
+	}
+
+	addFeedRequest struct {
+		Name        string `json:"name"`
+		URL         string `json:"url"`
+		Description string `json:"description"`
+	}
+
+	groupResponse struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		Feeds     []string  `json:"feeds"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	createGroupRequest struct {
+		Name string `json:"name"`
+	}
+
+	ruleResponse struct {
+		ID        string    `json:"id"`
+		FeedName  string    `json:"feed_name,omitempty"`
+		Group     string    `json:"group,omitempty"`
+		Field     string    `json:"field"`
+		Match     string    `json:"match"`
+		Pattern   string    `json:"pattern"`
+		Action    string    `json:"action"`
+		Tag       string    `json:"tag,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// addRuleRequest scopes the rule to the feed or group, to all subscribed feeds when neither is given
+	addRuleRequest struct {
+		FeedName string `json:"feed_name"`
+		Group    string `json:"group"`
+		Field    string `json:"field"`
+		Match    string `json:"match"`
+		Pattern  string `json:"pattern"`
+		Action   string `json:"action"`
+		Tag      string `json:"tag"`
+	}
+
+	ruleTestResponse struct {
+		Rule     ruleResponse      `json:"rule"`
+		Articles []articleResponse `json:"articles"` // Stored articles matched by the rule
+	}
+
+	alertResponse struct {
+		ID         string    `json:"id"`
+		FeedName   string    `json:"feed_name,omitempty"`
+		Match      string    `json:"match"`
+		Pattern    string    `json:"pattern"`
+		WebhookURL string    `json:"webhook_url"`
+		Secret     string    `json:"secret,omitempty"` // Key of the payload signatures, returned only on creation
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	// addAlertRequest scopes the alert to the feed, to all subscribed feeds when it is not given, match is keyword by default
+	addAlertRequest struct {
+		FeedName   string `json:"feed_name"`
+		Match      string `json:"match"`
+		Pattern    string `json:"pattern"`
+		WebhookURL string `json:"webhook_url"`
+	}
+
+	deliveryResponse struct {
+		ID             string          `json:"id"`
+		Alert          alertResponse   `json:"alert"`
+		Article        articleResponse `json:"article"`
+		FeedName       string          `json:"feed_name"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		MaxAttempts    int             `json:"max_attempts"`
+		RunAt          time.Time       `json:"run_at"`
+		ResponseStatus int             `json:"response_status,omitempty"`
+		LastError      string          `json:"last_error,omitempty"`
+		CreatedAt      time.Time       `json:"created_at"`
+		UpdatedAt      time.Time       `json:"updated_at"`
+	}
+
+	articleResponse struct {
+		ID          string              `json:"id"`
+		GUID        string              `json:"guid"`
+		Title       string              `json:"title"`
+		Link        string              `json:"link"`
+		Description string              `json:"description"`
+		Content     string              `json:"content,omitempty"`
+		Author      string              `json:"author,omitempty"`
+		Categories  []string            `json:"categories"`
+		PublishedAt time.Time           `json:"published_at"`
+		Read        bool                `json:"read"`
+		Starred     bool                `json:"starred"`
+		Enclosures  []enclosureResponse `json:"enclosures,omitempty"`
+	}
+
+	markReadResponse struct {
+		Marked int64 `json:"marked"` // Number of newly read articles
+	}
+
+	searchResultResponse struct {
+		Article  articleResponse `json:"article"`
+		FeedName string          `json:"feed_name"`
+		Rank     float64         `json:"rank"`
+		Snippet  string          `json:"snippet"` // Matched words are wrapped in "**"
+	}
+
+	enclosureResponse struct {
+		URL    string `json:"url"`
+		Type   string `json:"type,omitempty"`
+		Length int64  `json:"length,omitempty"`
+		Kind   string `json:"kind"`
+	}
+
+	configResponse struct {
+		WorkerCount        int    `json:"worker_count"`
+		TimerInterval      string `json:"timer_interval"`
+		HostMaxConcurrency int    `json:"host_max_concurrency"`
+		HostMinSpacing     string `json:"host_min_spacing"`
+	}
+
+	// updateConfigRequest changes only the given fields, durations are in Go format, e.g. "5m"
+	updateConfigRequest struct {
+		WorkerCount        *int    `json:"worker_count"`
+		TimerInterval      *string `json:"timer_interval"`
+		HostMaxConcurrency *int    `json:"host_max_concurrency"`
+		HostMinSpacing     *string `json:"host_min_spacing"`
+	}
+
+	statusResponse struct {
+		Config        configResponse     `json:"config"`
+		HeldBackFeeds int                `json:"held_back_feeds"`
+		Jobs          map[string]int     `json:"jobs"`
+		Instances     []instanceResponse `json:"instances"`
+	}
+
+	instanceResponse struct {
+		ID        string    `json:"id"`
+		Host      string    `json:"host"`
+		PID       int       `json:"pid"`
+		StartedAt time.Time `json:"started_at"`
+		LastSeen  time.Time `json:"last_seen"`
+		Leases    int       `json:"leases"`
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+func newFeedResponse(feed *models.Feed) feedResponse {
+	resp := feedResponse{
+		ID:           feed.ID,
+		Name:         feed.Name,
+		Description:  feed.Description,
+		URL:          feed.URL,
+		Format:       feed.Format,
+		CreatedAt:    feed.CreatedAt,
+		UpdatedAt:    feed.UpdatedAt,
+		FailureCount: feed.FailureCount,
+		LastError:    feed.LastError,
+		NextFetchAt:  feed.NextFetchAt,
+		Disabled:     feed.Disabled,
+	}
+	if feed.Interval != nil {
+		resp.Interval = feed.Interval.String()
+	}
+	return resp
+}
+
+func newGroupResponse(group *models.Group) groupResponse {
+	feeds := group.FeedNames
+	if feeds == nil {
+		feeds = []string{}
+	}
+
+	return groupResponse{
+		ID:        group.ID,
+		Name:      group.Name,
+		Feeds:     feeds,
+		CreatedAt: group.CreatedAt,
+	}
+}
+
+func newRuleResponse(rule *models.Rule) ruleResponse {
+	return ruleResponse{
+		ID:        rule.ID,
+		FeedName:  rule.FeedName,
+		Group:     rule.Group,
+		Field:     rule.Field,
+		Match:     rule.Match,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		Tag:       rule.Tag,
+		CreatedAt: rule.CreatedAt,
+	}
+}
+
+func newAlertResponse(alert *models.Alert) alertResponse {
+	return alertResponse{
+		ID:         alert.ID,
+		FeedName:   alert.FeedName,
+		Match:      alert.Match,
+		Pattern:    alert.Pattern,
+		WebhookURL: alert.WebhookURL,
+		CreatedAt:  alert.CreatedAt,
+	}
+}
+
+func newDeliveryResponse(delivery *models.AlertDelivery) deliveryResponse {
+	return deliveryResponse{
+		ID:             delivery.ID,
+		Alert:          newAlertResponse(delivery.Alert),
+		Article:        newArticleResponse(delivery.Article),
+		FeedName:       delivery.Article.FeedName,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		MaxAttempts:    delivery.MaxAttempts,
+		RunAt:          delivery.RunAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
+
+func newArticleResponse(article *models.RSSItem) articleResponse {
+	resp := articleResponse{
+		ID:          article.ID,
+		GUID:        article.GUID,
+		Title:       article.Title,
+		Link:        article.Link,
+		Description: article.Description,
+		Content:     article.Content,
+		Author:      article.Author,
+		Categories:  article.Categories,
+		PublishedAt: article.PublishedAt,
+		Read:        article.Read,
+		Starred:     article.Starred,
+	}
+	if resp.Categories == nil {
+		resp.Categories = []string{}
+	}
+	for _, e := range article.Enclosures {
+		resp.Enclosures = append(resp.Enclosures, enclosureResponse(e))
+	}
+	return resp
+}
+
+func newConfigResponse(cfg *models.RssConfig) configResponse {
+	return configResponse{
+		WorkerCount:        cfg.WorkerCount,
+		TimerInterval:      cfg.TimerInterval.String(),
+		HostMaxConcurrency: cfg.HostMaxConcurrency,
+		HostMinSpacing:     cfg.HostMinSpacing.String(),
+	}
+}
+
+func newStatusResponse(status *models.AggregatorStatus) statusResponse {
+	resp := statusResponse{
+		Config:        newConfigResponse(status.Config),
+		HeldBackFeeds: status.HeldBackFeeds,
+		Jobs:          status.Jobs,
+		Instances:     make([]instanceResponse, 0, len(status.Instances)),
+	}
+	for _, instance := range status.Instances {
+		resp.Instances = append(resp.Instances, instanceResponse(*instance))
+	}
+	return resp
+}
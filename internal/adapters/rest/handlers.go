@@ -0,0 +1,702 @@
+package rest
+
+import (
+	"RSSHub/internal/domain/models"
+	"RSSHub/pkg/utils"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRequestBody limits the size of JSON request bodies
+const maxRequestBody = 1 << 20
+
+// outputSize is the default number of articles in output documents
+const outputSize = 50
+
+// minInterval is the shortest allowed fetch interval, as in the CLI
+const minInterval = 2 * time.Minute
+
+// handleListFeeds lists subscribed feeds, the group query parameter narrows them to the group
+func (s *Server) handleListFeeds(w http.ResponseWriter, r *http.Request) {
+	num, err := queryInt(r, "num")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	filter := models.FeedFilter{
+		Group: r.URL.Query().Get("group"),
+		Num:   num,
+	}
+
+	feeds, err := s.aggregator.ListFeeds(requestUser(r), filter)
+	if err != nil && !errors.Is(err, models.ErrFeedsNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]feedResponse, 0, len(feeds))
+	for _, feed := range feeds {
+		resp = append(resp, newFeedResponse(feed))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleAddFeed(w http.ResponseWriter, r *http.Request) {
+	var req addFeedRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	switch {
+	case req.Name == "":
+		s.writeError(w, r, http.StatusBadRequest, errors.New("name is required"))
+		return
+	case req.URL == "":
+		s.writeError(w, r, http.StatusBadRequest, errors.New("url is required"))
+		return
+	case req.Description == "":
+		s.writeError(w, r, http.StatusBadRequest, errors.New("description is required"))
+		return
+	}
+
+	// The feed keeps its own name when another user already added its URL
+	feed, err := s.aggregator.AddFeed(requestUser(r), req.Name, req.Description, req.URL)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/feeds/"+feed.Name)
+	s.writeJSON(w, r, http.StatusCreated, newFeedResponse(feed))
+}
+
+func (s *Server) handleDeleteFeed(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.DeleteFeed(requestUser(r), r.PathValue("name")); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleArticles(w http.ResponseWriter, r *http.Request) {
+	s.writeArticles(w, r, models.ArticleFilter{FeedName: r.PathValue("name")})
+}
+
+// handleGroupArticles lists articles of all feeds in the group merged by publication date
+func (s *Server) handleGroupArticles(w http.ResponseWriter, r *http.Request) {
+	s.writeArticles(w, r, models.ArticleFilter{Group: r.PathValue("name")})
+}
+
+// writeArticles lists articles of the feed or group of the filter narrowed by the query parameters
+func (s *Server) writeArticles(w http.ResponseWriter, r *http.Request, filter models.ArticleFilter) {
+	num, err := queryInt(r, "num")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filter.Num = num
+	filter.Category = query.Get("category")
+	filter.WithMedia = query.Get("with_media") == "true"
+	filter.Unread = query.Get("unread") == "true"
+	filter.Starred = query.Get("starred") == "true"
+
+	articles, err := s.aggregator.GetArticles(requestUser(r), filter)
+	if err != nil && !errors.Is(err, models.ErrArticlesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]articleResponse, 0, len(articles))
+	for _, article := range articles {
+		resp = append(resp, newArticleResponse(article))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := s.aggregator.ListGroups(requestUser(r))
+	if err != nil && !errors.Is(err, models.ErrGroupsNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]groupResponse, 0, len(groups))
+	for _, group := range groups {
+		resp = append(resp, newGroupResponse(group))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req createGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	user := requestUser(r)
+	if err := s.aggregator.CreateGroup(user, req.Name); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	group, err := s.aggregator.GetGroup(user, req.Name)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/groups/"+group.Name)
+	s.writeJSON(w, r, http.StatusCreated, newGroupResponse(group))
+}
+
+func (s *Server) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.DeleteGroup(requestUser(r), r.PathValue("name")); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGroupFeed adds the feed to the group on PUT and removes it on DELETE
+func (s *Server) handleGroupFeed(w http.ResponseWriter, r *http.Request) {
+	user, group, feed := requestUser(r), r.PathValue("name"), r.PathValue("feed")
+
+	var err error
+	if r.Method == http.MethodPut {
+		err = s.aggregator.AddGroupFeed(user, group, feed)
+	} else {
+		err = s.aggregator.RemoveGroupFeed(user, group, feed)
+	}
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := s.aggregator.ListRules(requestUser(r))
+	if err != nil && !errors.Is(err, models.ErrRulesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]ruleResponse, 0, len(rules))
+	for _, rule := range rules {
+		resp = append(resp, newRuleResponse(rule))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleAddRule(w http.ResponseWriter, r *http.Request) {
+	var req addRuleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rule, err := s.aggregator.AddRule(requestUser(r), &models.Rule{
+		FeedName: req.FeedName,
+		Group:    req.Group,
+		Field:    req.Field,
+		Match:    req.Match,
+		Pattern:  req.Pattern,
+		Action:   req.Action,
+		Tag:      req.Tag,
+	})
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/rules/"+rule.ID)
+	s.writeJSON(w, r, http.StatusCreated, newRuleResponse(rule))
+}
+
+func (s *Server) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.DeleteRule(requestUser(r), r.PathValue("id")); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestRule dry-runs the rule against stored articles, nothing is changed
+func (s *Server) handleTestRule(w http.ResponseWriter, r *http.Request) {
+	rule, articles, err := s.aggregator.TestRule(requestUser(r), r.PathValue("id"))
+	if err != nil && !errors.Is(err, models.ErrArticlesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := ruleTestResponse{
+		Rule:     newRuleResponse(rule),
+		Articles: make([]articleResponse, 0, len(articles)),
+	}
+	for _, article := range articles {
+		resp.Articles = append(resp.Articles, newArticleResponse(article))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleListAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := s.aggregator.ListAlerts(requestUser(r))
+	if err != nil && !errors.Is(err, models.ErrAlertsNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]alertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		resp = append(resp, newAlertResponse(alert))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+// handleAddAlert creates the alert, its signing secret is in the response only here
+func (s *Server) handleAddAlert(w http.ResponseWriter, r *http.Request) {
+	var req addAlertRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Match == "" {
+		req.Match = models.AlertMatchKeyword
+	}
+
+	alert, err := s.aggregator.AddAlert(requestUser(r), &models.Alert{
+		FeedName:   req.FeedName,
+		Match:      req.Match,
+		Pattern:    req.Pattern,
+		WebhookURL: req.WebhookURL,
+	})
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := newAlertResponse(alert)
+	resp.Secret = alert.Secret
+
+	w.Header().Set("Location", "/alerts/"+alert.ID)
+	s.writeJSON(w, r, http.StatusCreated, resp)
+}
+
+func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.DeleteAlert(requestUser(r), r.PathValue("id")); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListDeliveries lists webhook deliveries of alerts, status and num query parameters narrow the list
+func (s *Server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	num, err := queryInt(r, "num")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	filter := models.DeliveryFilter{Status: r.URL.Query().Get("status"), Num: num}
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliveryRunning, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		s.writeError(w, r, http.StatusBadRequest, errors.New("status must be one of pending, running, delivered, failed"))
+		return
+	}
+
+	deliveries, err := s.aggregator.ListDeliveries(requestUser(r), filter)
+	if err != nil && !errors.Is(err, models.ErrDeliveriesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]deliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, newDeliveryResponse(delivery))
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+// handleMarkRead marks articles of the feed as read, those published before the before query parameter (RFC 3339) when it is given
+func (s *Server) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	before := time.Now()
+	if value := r.URL.Query().Get("before"); value != "" {
+		var err error
+		if before, err = time.Parse(time.RFC3339, value); err != nil {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("before must be an RFC 3339 time"))
+			return
+		}
+	}
+
+	marked, err := s.aggregator.MarkRead(requestUser(r), r.PathValue("name"), before)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, markReadResponse{Marked: marked})
+}
+
+// handleStar stars the article on PUT and unstars it on DELETE
+func (s *Server) handleStar(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.StarArticle(requestUser(r), r.PathValue("id"), r.Method == http.MethodPut); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// defaultSearchNum limits search results when num is not given
+const defaultSearchNum = 20
+
+// handleSearch finds articles by the q query parameter, feed, since (e.g. "7d") and num narrow the results
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.SearchFilter{
+		Query:    query.Get("q"),
+		FeedName: query.Get("feed"),
+		Num:      defaultSearchNum,
+	}
+	if strings.TrimSpace(filter.Query) == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+
+	num, err := queryInt(r, "num")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if num > 0 {
+		filter.Num = num
+	}
+
+	if since := query.Get("since"); since != "" {
+		age, err := utils.ParseAge(since)
+		if err != nil || age <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("since must be a positive duration, e.g. 7d or 12h"))
+			return
+		}
+		filter.Since = time.Now().Add(-age)
+	}
+
+	results, err := s.aggregator.SearchArticles(requestUser(r), filter)
+	if err != nil && !errors.Is(err, models.ErrArticlesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := make([]searchResultResponse, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, searchResultResponse{
+			Article:  newArticleResponse(result.Article),
+			FeedName: result.Article.FeedName,
+			Rank:     result.Rank,
+			Snippet:  result.Snippet,
+		})
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+// handleAllOutput renders articles of all subscribed feeds merged
+func (s *Server) handleAllOutput(w http.ResponseWriter, r *http.Request) {
+	self := requestURL(r)
+	feed := &outputFeed{
+		ID:          strings.TrimSuffix(self, "?"+r.URL.RawQuery),
+		Title:       "RSSHub: all feeds",
+		Description: "Articles of all feeds of " + requestUser(r) + " aggregated by RSSHub",
+		Link:        self,
+		Self:        self,
+	}
+
+	s.writeOutput(w, r, feed, models.ArticleFilter{})
+}
+
+// handleFeedOutput renders articles of one feed
+func (s *Server) handleFeedOutput(w http.ResponseWriter, r *http.Request) {
+	source, err := s.aggregator.GetFeed(requestUser(r), r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	feed := &outputFeed{
+		ID:          "urn:uuid:" + source.ID,
+		Title:       source.Name,
+		Description: source.Description,
+		Link:        source.URL,
+		Self:        requestURL(r),
+	}
+
+	s.writeOutput(w, r, feed, models.ArticleFilter{FeedName: source.Name})
+}
+
+// handleGroupOutput renders articles of all feeds in the group merged
+func (s *Server) handleGroupOutput(w http.ResponseWriter, r *http.Request) {
+	group, err := s.aggregator.GetGroup(requestUser(r), r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	self := requestURL(r)
+	feed := &outputFeed{
+		ID:          "urn:uuid:" + group.ID,
+		Title:       "RSSHub: " + group.Name,
+		Description: "Articles of the feeds in the group " + group.Name,
+		Link:        self,
+		Self:        self,
+	}
+
+	s.writeOutput(w, r, feed, models.ArticleFilter{Group: group.Name})
+}
+
+// writeOutput loads articles matching the filter into the feed and writes it in the format of the request path,
+// num and category query parameters narrow the articles
+func (s *Server) writeOutput(w http.ResponseWriter, r *http.Request, feed *outputFeed, filter models.ArticleFilter) {
+	num, err := queryInt(r, "num")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if num == 0 {
+		num = outputSize
+	}
+	filter.Num = num
+	filter.Category = r.URL.Query().Get("category")
+
+	feed.Articles, err = s.aggregator.GetArticles(requestUser(r), filter)
+	if err != nil && !errors.Is(err, models.ErrArticlesNotFound) {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	var (
+		doc         any
+		contentType string
+	)
+	switch path.Base(r.URL.Path) {
+	case outputRSS:
+		doc, contentType = newRSSDocument(feed), "application/rss+xml; charset=utf-8"
+	case outputAtom:
+		doc, contentType = newAtomFeed(feed), "application/atom+xml; charset=utf-8"
+	default:
+		s.writeError(w, r, http.StatusNotFound, errors.New("unknown output format"))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeXML(w, doc); err != nil {
+		s.log.Error(r.Context(), "Failed to write feed document", "error", err)
+	}
+}
+
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := s.aggregator.GetConfig(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, newConfigResponse(cfg))
+}
+
+// handleUpdateConfig applies the given fields, all of them are validated before the config is replaced at once
+func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var req updateConfigRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	cfg, err := s.aggregator.GetConfig(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	if req.TimerInterval != nil {
+		interval, err := time.ParseDuration(*req.TimerInterval)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid timer_interval: %w", err))
+			return
+		}
+		if interval < minInterval {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("invalid timer_interval, must be at least 2 min"))
+			return
+		}
+		cfg.TimerInterval = interval
+	}
+
+	if req.WorkerCount != nil {
+		if cfg.WorkerCount = *req.WorkerCount; cfg.WorkerCount < 1 {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("worker_count must be greater than 0"))
+			return
+		}
+	}
+
+	if req.HostMaxConcurrency != nil {
+		if cfg.HostMaxConcurrency = *req.HostMaxConcurrency; cfg.HostMaxConcurrency < 1 {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("host_max_concurrency must be greater than 0"))
+			return
+		}
+	}
+	if req.HostMinSpacing != nil {
+		minSpacing, err := time.ParseDuration(*req.HostMinSpacing)
+		if err != nil || minSpacing < 0 {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("invalid host_min_spacing, must be a non-negative duration"))
+			return
+		}
+		cfg.HostMinSpacing = minSpacing
+	}
+
+	if err := s.aggregator.UpdateConfig(cfg); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.handleGetConfig(w, r)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.aggregator.GetStatus(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, newStatusResponse(status))
+}
+
+func (s *Server) handleFetchStart(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.Start(r.Context()); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleFetchStop(w http.ResponseWriter, r *http.Request) {
+	if err := s.aggregator.Stop(); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeServiceError maps errors of the aggregator to HTTP statuses
+func (s *Server) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrInvalidRule),
+		errors.Is(err, models.ErrInvalidConfig),
+		errors.Is(err, models.ErrInvalidAlert):
+		status = http.StatusBadRequest
+	case errors.Is(err, models.ErrFeedNotFound),
+		errors.Is(err, models.ErrGroupNotFound),
+		errors.Is(err, models.ErrArticleNotFound),
+		errors.Is(err, models.ErrUserNotFound),
+		errors.Is(err, models.ErrRuleNotFound),
+		errors.Is(err, models.ErrAlertNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidToken):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="rsshub"`)
+		status = http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, models.ErrFeedExists),
+		errors.Is(err, models.ErrSubscribed),
+		errors.Is(err, models.ErrGroupExists),
+		errors.Is(err, models.ErrAlreadyRunning),
+		errors.Is(err, models.ErrNotRunning):
+		status = http.StatusConflict
+	}
+
+	s.writeError(w, r, status, err)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	s.writeJSON(w, r, status, errorResponse{Error: err.Error()})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.log.Error(r.Context(), "Failed to write response", "error", err)
+	}
+}
+
+// writeXML writes the document with the XML declaration
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// decodeJSON reads the request body into dst, unknown fields are refused
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// queryInt returns a non-negative integer query parameter, 0 when it is missing
+func queryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,180 @@
+// Package rest exposes the aggregator operations over JSON HTTP.
+package rest
+
+import (
+	"RSSHub/internal/domain/models"
+	"RSSHub/internal/domain/ports"
+	"RSSHub/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Addr string `env:"HTTP_ADDR" default:":8080"`
+}
+
+// Server is the HTTP API server
+type Server struct {
+	aggregator ports.Aggregator
+	srv        *http.Server
+
+	log logger.Logger
+}
+
+func NewServer(cfg Config, aggregator ports.Aggregator, log logger.Logger) *Server {
+	s := &Server{
+		aggregator: aggregator,
+		log:        log,
+	}
+
+	s.srv = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+
+	return s
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /feeds", s.authenticate(s.handleListFeeds))
+	mux.HandleFunc("POST /feeds", s.authenticate(s.handleAddFeed))
+	mux.HandleFunc("DELETE /feeds/{name}", s.authenticate(s.handleDeleteFeed))
+	mux.HandleFunc("GET /feeds/{name}/articles", s.authenticate(s.handleArticles))
+	mux.HandleFunc("POST /feeds/{name}/read", s.authenticate(s.handleMarkRead))
+	mux.HandleFunc("PUT /articles/{id}/star", s.authenticate(s.handleStar))
+	mux.HandleFunc("DELETE /articles/{id}/star", s.authenticate(s.handleStar))
+	mux.HandleFunc("GET /search", s.authenticate(s.handleSearch))
+
+	mux.HandleFunc("GET /rules", s.authenticate(s.handleListRules))
+	mux.HandleFunc("POST /rules", s.authenticate(s.handleAddRule))
+	mux.HandleFunc("DELETE /rules/{id}", s.authenticate(s.handleDeleteRule))
+	mux.HandleFunc("GET /rules/{id}/test", s.authenticate(s.handleTestRule))
+
+	mux.HandleFunc("GET /alerts", s.authenticate(s.handleListAlerts))
+	mux.HandleFunc("POST /alerts", s.authenticate(s.handleAddAlert))
+	mux.HandleFunc("DELETE /alerts/{id}", s.authenticate(s.handleDeleteAlert))
+	mux.HandleFunc("GET /alerts/deliveries", s.authenticate(s.handleListDeliveries))
+
+	mux.HandleFunc("GET /groups", s.authenticate(s.handleListGroups))
+	mux.HandleFunc("POST /groups", s.authenticate(s.handleCreateGroup))
+	mux.HandleFunc("DELETE /groups/{name}", s.authenticate(s.handleDeleteGroup))
+	mux.HandleFunc("GET /groups/{name}/articles", s.authenticate(s.handleGroupArticles))
+	mux.HandleFunc("PUT /groups/{name}/feeds/{feed}", s.authenticate(s.handleGroupFeed))
+	mux.HandleFunc("DELETE /groups/{name}/feeds/{feed}", s.authenticate(s.handleGroupFeed))
+
+	// Stored articles republished as feed documents, feed readers may pass the token in the query
+	mux.HandleFunc("GET /rss", s.authenticateOutput(s.handleAllOutput))
+	mux.HandleFunc("GET /atom", s.authenticateOutput(s.handleAllOutput))
+	mux.HandleFunc("GET /feeds/{name}/rss", s.authenticateOutput(s.handleFeedOutput))
+	mux.HandleFunc("GET /feeds/{name}/atom", s.authenticateOutput(s.handleFeedOutput))
+	mux.HandleFunc("GET /groups/{name}/rss", s.authenticateOutput(s.handleGroupOutput))
+	mux.HandleFunc("GET /groups/{name}/atom", s.authenticateOutput(s.handleGroupOutput))
+
+	// Fetching is shared by all users, only admins manage it
+	mux.HandleFunc("GET /config", s.authenticateAdmin(s.handleGetConfig))
+	mux.HandleFunc("PUT /config", s.authenticateAdmin(s.handleUpdateConfig))
+	mux.HandleFunc("GET /status", s.authenticateAdmin(s.handleStatus))
+
+	mux.HandleFunc("POST /fetch/start", s.authenticateAdmin(s.handleFetchStart))
+	mux.HandleFunc("POST /fetch/stop", s.authenticateAdmin(s.handleFetchStop))
+
+	return s.logRequests(mux)
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return s.srv.Addr
+}
+
+// ListenAndServe serves requests until Shutdown is called
+func (s *Server) ListenAndServe() error {
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for active requests to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+type userKey struct{}
+
+// authenticate serves the request for the user owning the bearer token of the Authorization header
+func (s *Server) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return s.authenticateWith(next, false, false)
+}
+
+// authenticateAdmin serves the request only for admins, other users are forbidden
+func (s *Server) authenticateAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return s.authenticateWith(next, false, true)
+}
+
+// authenticateOutput also accepts the token in the token query parameter, as feed readers rarely set headers
+func (s *Server) authenticateOutput(next http.HandlerFunc) http.HandlerFunc {
+	return s.authenticateWith(next, true, false)
+}
+
+func (s *Server) authenticateWith(next http.HandlerFunc, queryToken, admin bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && queryToken {
+			token = r.URL.Query().Get("token")
+		}
+
+		user, err := s.aggregator.Authenticate(token)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		if admin && !user.Admin {
+			s.writeServiceError(w, r, models.ErrForbidden)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user.Name)))
+	}
+}
+
+// requestUser returns name of the authenticated user of the request
+func requestUser(r *http.Request) string {
+	user, _ := r.Context().Value(userKey{}).(string)
+	return user
+}
+
+// logRequests logs every served request with its status and duration
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		s.log.Info(r.Context(), "request served",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,234 @@
+package rest
+
+import (
+	"RSSHub/internal/domain/models"
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// generator is written to output documents
+const generator = "RSSHub"
+
+// Formats of output documents, the last segment of the request path
+const (
+	outputRSS  = "rss"
+	outputAtom = "atom"
+)
+
+// outputFeed is a list of stored articles republished as a feed document
+type outputFeed struct {
+	ID          string // Permanent IRI of the document, used by Atom
+	Title       string
+	Description string
+	Link        string // Source of the articles
+	Self        string // URL the document is served from
+	Articles    []*models.RSSItem
+}
+
+// updated returns the publication time of the latest article, now for an empty feed
+func (f *outputFeed) updated() time.Time {
+	var updated time.Time
+	for _, article := range f.Articles {
+		if article.PublishedAt.After(updated) {
+			updated = article.PublishedAt
+		}
+	}
+	if updated.IsZero() {
+		return time.Now()
+	}
+	return updated
+}
+
+type (
+	rssDocument struct {
+		XMLName   xml.Name   `xml:"rss"`
+		Version   string     `xml:"version,attr"`
+		AtomNS    string     `xml:"xmlns:atom,attr"`
+		DCNS      string     `xml:"xmlns:dc,attr"`
+		ContentNS string     `xml:"xmlns:content,attr"`
+		Channel   rssChannel `xml:"channel"`
+	}
+
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		Self          atomLink  `xml:"atom:link"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Generator     string    `xml:"generator"`
+		Items         []rssItem `xml:"item"`
+	}
+
+	rssItem struct {
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link,omitempty"`
+		Description string   `xml:"description"`
+		Content     string   `xml:"content:encoded,omitempty"`
+		Creator     string   `xml:"dc:creator,omitempty"`
+		Categories  []string `xml:"category"`
+		GUID        rssGUID  `xml:"guid"`
+		PubDate     string   `xml:"pubDate"`
+	}
+
+	rssGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+)
+
+type (
+	atomFeed struct {
+		XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		ID        string      `xml:"id"`
+		Title     string      `xml:"title"`
+		Subtitle  string      `xml:"subtitle,omitempty"`
+		Updated   string      `xml:"updated"`
+		Author    atomPerson  `xml:"author"`
+		Generator string      `xml:"generator"`
+		Links     []atomLink  `xml:"link"`
+		Entries   []atomEntry `xml:"entry"`
+	}
+
+	atomEntry struct {
+		ID         string         `xml:"id"`
+		Title      atomText       `xml:"title"`
+		Updated    string         `xml:"updated"`
+		Published  string         `xml:"published"`
+		Links      []atomLink     `xml:"link"`
+		Authors    []atomPerson   `xml:"author"`
+		Categories []atomCategory `xml:"category"`
+		Summary    *atomText      `xml:"summary"`
+		Content    *atomText      `xml:"content"`
+		Source     *atomSource    `xml:"source"`
+	}
+
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+		Type string `xml:"type,attr,omitempty"`
+	}
+
+	atomPerson struct {
+		Name string `xml:"name"`
+	}
+
+	atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+
+	// atomText is a text construct, HTML is escaped in the body
+	atomText struct {
+		Type string `xml:"type,attr"`
+		Body string `xml:",chardata"`
+	}
+
+	atomSource struct {
+		Title string `xml:"title"`
+	}
+)
+
+// newRSSDocument renders the feed as RSS 2.0
+func newRSSDocument(feed *outputFeed) *rssDocument {
+	description := feed.Description
+	if description == "" {
+		description = feed.Title
+	}
+
+	doc := &rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   description,
+			Self:          atomLink{Href: feed.Self, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: feed.updated().UTC().Format(time.RFC1123Z),
+			Generator:     generator,
+			Items:         make([]rssItem, 0, len(feed.Articles)),
+		},
+	}
+
+	for _, article := range feed.Articles {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       article.Title,
+			Link:        article.Link,
+			Description: article.Description,
+			Content:     article.Content,
+			Creator:     article.Author,
+			Categories:  article.Categories,
+			GUID:        rssGUID{Value: articleIRI(article)},
+			PubDate:     article.PublishedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return doc
+}
+
+// newAtomFeed renders the feed as Atom 1.0
+func newAtomFeed(feed *outputFeed) *atomFeed {
+	doc := &atomFeed{
+		ID:        feed.ID,
+		Title:     feed.Title,
+		Subtitle:  feed.Description,
+		Updated:   feed.updated().UTC().Format(time.RFC3339),
+		Author:    atomPerson{Name: generator},
+		Generator: generator,
+		Links: []atomLink{
+			{Href: feed.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Articles)),
+	}
+
+	for _, article := range feed.Articles {
+		published := article.PublishedAt.UTC().Format(time.RFC3339)
+		entry := atomEntry{
+			ID:        articleIRI(article),
+			Title:     atomText{Type: "html", Body: article.Title},
+			Updated:   published,
+			Published: published,
+			Source:    &atomSource{Title: article.FeedName},
+		}
+
+		if article.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: article.Link, Rel: "alternate"})
+		}
+		if article.Author != "" {
+			entry.Authors = append(entry.Authors, atomPerson{Name: article.Author})
+		}
+		for _, category := range article.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if article.Description != "" {
+			entry.Summary = &atomText{Type: "html", Body: article.Description}
+		}
+		if article.Content != "" {
+			entry.Content = &atomText{Type: "html", Body: article.Content}
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return doc
+}
+
+// articleIRI is the permanent identity of the stored article, it is unique across all feeds
+func articleIRI(article *models.RSSItem) string {
+	return "urn:uuid:" + article.ID
+}
+
+// requestURL rebuilds the absolute URL of the request, the scheme may be set by a reverse proxy
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
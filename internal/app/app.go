@@ -4,8 +4,11 @@ import (
 	"RSSHub/config"
 	"RSSHub/internal/adapters/cli"
 	"RSSHub/internal/adapters/repo"
+	"RSSHub/internal/adapters/rest"
 	"RSSHub/internal/service"
+	"RSSHub/migrations"
 	"RSSHub/pkg/logger"
+	"RSSHub/pkg/migrator"
 	"RSSHub/pkg/postgres"
 	"context"
 	"fmt"
@@ -35,14 +38,40 @@ func New(ctx context.Context, cfg *config.Config, logger logger.Logger) (*App, e
 	articleRepo := repo.NewArticleRepo(db.Pool)
 	feedRepo := repo.NewFeedRepo(db.Pool)
 	configRepo := repo.NewConfigRepo(db.Pool)
+	jobRepo := repo.NewJobRepo(db.Pool)
+	instanceRepo := repo.NewInstanceRepo(db.Pool)
+	groupRepo := repo.NewGroupRepo(db.Pool)
+	userRepo := repo.NewUserRepo(db.Pool)
+	ruleRepo := repo.NewRuleRepo(db.Pool)
+	alertRepo := repo.NewAlertRepo(db.Pool)
 
 	// Services
-	aggregator := service.NewRssAggregator(articleRepo, feedRepo, configRepo, logger, func() {
-		db.Close()
-	})
+	aggregator := service.NewRssAggregator(articleRepo, feedRepo, configRepo, jobRepo, instanceRepo, groupRepo, userRepo, ruleRepo, alertRepo, logger)
+
+	// Schema migrations embedded in the binary
+	schema, err := migrator.New(db.Pool, migrations.FS)
+	if err != nil {
+		log.Error("failed to load migrations", "error", err)
+		return nil, fmt.Errorf("failed to load migrations")
+	}
+
+	// HTTP API, served by "rsshub serve"
+	server := rest.NewServer(cfg.HTTP, aggregator, logger)
 
 	// CLI Handler
-	cliHandler := cli.NewCLIHandler(aggregator, logger)
+	cliHandler := cli.NewCLIHandler(cfg.CLI, aggregator, schema, server, logger)
+
+	// Schema is brought up to date, unless it is managed by hand with "rsshub migrate"
+	if cfg.Postgres.AutoMigrate && !cliHandler.IsMigrateCommand() {
+		applied, err := schema.Up(ctx)
+		if err != nil {
+			log.Error("failed to apply migrations", "error", err)
+			return nil, fmt.Errorf("failed to apply migrations")
+		}
+		for _, m := range applied {
+			log.Info("migration applied", "version", m.Version, "name", m.Name)
+		}
+	}
 
 	return &App{
 		cliHandler: cliHandler,
@@ -55,6 +84,8 @@ func New(ctx context.Context, cfg *config.Config, logger logger.Logger) (*App, e
 }
 
 func (app *App) Run(ctx context.Context) error {
+	defer app.postgresDB.Close()
+
 	// Running CLI
 	if err := app.cliHandler.ParseFlags(); err != nil {
 		return err
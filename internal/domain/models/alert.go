@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Ways alerts match articles, both are case insensitive and look at titles and descriptions
+const (
+	AlertMatchKeyword = "keyword" // Title or description contains the pattern
+	AlertMatchRegex   = "regex"   // Title or description matches the regular expression
+)
+
+// Alert posts newly stored articles matching its pattern to the webhook of its owner
+type Alert struct {
+	ID         string
+	UserID     string
+	FeedName   string // Only articles of the feed, empty means any subscribed feed
+	Match      string
+	Pattern    string
+	WebhookURL string
+	Secret     string // Key of the HMAC-SHA256 signature of payloads
+	CreatedAt  time.Time
+}
+
+// Alert delivery statuses
+const (
+	DeliveryPending   = "pending"   // Waiting for its run time
+	DeliveryRunning   = "running"   // Being posted to the webhook
+	DeliveryDelivered = "delivered" // Webhook answered with a 2xx status
+	DeliveryFailed    = "failed"    // All attempts failed, the delivery is not retried anymore
+)
+
+// AlertDelivery is a persisted webhook request for an article matched by an alert
+type AlertDelivery struct {
+	ID             string
+	AlertID        string
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	RunAt          time.Time
+	ResponseStatus int // HTTP status of the last attempt, 0 when no response was received
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Alert   *Alert   // Alert of the delivery, the secret is filled only when the delivery is claimed
+	Article *RSSItem // Matched article
+}
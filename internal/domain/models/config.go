@@ -6,7 +6,17 @@ import (
 
 // RssConfig represent config stored in database
 type RssConfig struct {
-	Run           bool
 	WorkerCount   int
 	TimerInterval time.Duration
+
+	HostMaxConcurrency int           // Maximum of concurrent requests to the same host
+	HostMinSpacing     time.Duration // Minimal time between requests to the same host
+}
+
+// AggregatorStatus represent current state of the aggregator
+type AggregatorStatus struct {
+	Config        *RssConfig
+	HeldBackFeeds int            // Feeds due by fetch interval but held back by publisher hints
+	Jobs          map[string]int // Number of fetch jobs by status
+	Instances     []*Instance    // Running aggregators, empty when stopped
 }
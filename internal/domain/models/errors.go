@@ -0,0 +1,31 @@
+package models
+
+import "errors"
+
+// Errors of the aggregator operations which callers may tell apart
+var (
+	ErrAlreadyRunning     = errors.New("background process already running")
+	ErrNotRunning         = errors.New("background process is not running")
+	ErrUserNotFound       = errors.New("the user is not exist")
+	ErrUserExists         = errors.New("user name must be unique")
+	ErrInvalidToken       = errors.New("API token is invalid")
+	ErrForbidden          = errors.New("only admins are allowed to do it")
+	ErrSubscribed         = errors.New("already subscribed to the feed")
+	ErrFeedNotFound       = errors.New("the feed is not exist")
+	ErrFeedExists         = errors.New("feed name must be unique")
+	ErrGroupNotFound      = errors.New("the group is not exist")
+	ErrGroupExists        = errors.New("group name must be unique")
+	ErrGroupsNotFound     = errors.New("groups are not found")
+	ErrFeedsNotFound      = errors.New("feeds are not found")
+	ErrArticleNotFound    = errors.New("the article is not exist")
+	ErrArticlesNotFound   = errors.New("articles are not found")
+	ErrJobsNotFound       = errors.New("there are no fetch jobs")
+	ErrRuleNotFound       = errors.New("the rule is not exist")
+	ErrRulesNotFound      = errors.New("rules are not found")
+	ErrInvalidRule        = errors.New("rule is invalid")
+	ErrInvalidConfig      = errors.New("config is invalid")
+	ErrAlertNotFound      = errors.New("the alert is not exist")
+	ErrAlertsNotFound     = errors.New("alerts are not found")
+	ErrInvalidAlert       = errors.New("alert is invalid")
+	ErrDeliveriesNotFound = errors.New("there are no alert deliveries")
+)
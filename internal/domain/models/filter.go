@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// ArticleFilter describes which articles should be retrieved
+type ArticleFilter struct {
+	UserID    string // Only articles of feeds the user is subscribed to, states are of the user
+	FeedName  string // Only articles of the feed, empty means any
+	Group     string // Only articles of feeds in the group, empty means any
+	Num       int    // Limit of articles, 0 means no limit
+	WithMedia bool   // Only articles with enclosures, enclosures are loaded
+	Category  string // Only articles of the category, case insensitive
+	Unread    bool   // Only articles not marked as read
+	Starred   bool   // Only starred articles
+}
+
+// SearchFilter describes which articles should be found
+type SearchFilter struct {
+	UserID   string    // Only articles of feeds the user is subscribed to, states are of the user
+	Query    string    // Web search style query, e.g. "golang generics -rust"
+	FeedName string    // Only articles of the feed, empty means any
+	Since    time.Time // Only articles published since the time, zero means any
+	Num      int       // Limit of articles, 0 means no limit
+}
+
+// FeedFilter describes which subscribed feeds should be retrieved
+type FeedFilter struct {
+	Group string // Only feeds in the group, empty means any
+	Num   int    // Limit of feeds, 0 means no limit
+}
+
+// JobFilter describes which fetch jobs should be retrieved
+type JobFilter struct {
+	Status string // Only jobs in the status, empty means any
+	Num    int    // Limit of jobs, 0 means no limit
+}
+
+// DeliveryFilter describes which alert deliveries should be retrieved
+type DeliveryFilter struct {
+	UserID string // Only deliveries of alerts of the user
+	Status string // Only deliveries in the status, empty means any
+	Num    int    // Limit of deliveries, 0 means no limit
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Group is a named set of feeds, e.g. "golang"
+type Group struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	FeedNames []string // Names of the member feeds
+}
+
+// Outcomes of importing a subscription
+const (
+	ImportAdded   = "added"   // Feed is created
+	ImportSkipped = "skipped" // Feed with the same name or URL already exists
+	ImportFailed  = "failed"  // Feed could not be created
+)
+
+// ImportResult is the outcome of importing one subscription
+type ImportResult struct {
+	Name   string
+	URL    string
+	Group  string // Group the feed is added to, empty for top level subscriptions
+	Status string
+	Reason string // Why the feed is skipped or failed
+}
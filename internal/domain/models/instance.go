@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Instance is a running aggregator process
+type Instance struct {
+	ID        string
+	Host      string
+	PID       int
+	StartedAt time.Time
+	LastSeen  time.Time // Time of the last heartbeat
+	Leases    int       // Feeds currently leased by the instance
+}
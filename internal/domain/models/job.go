@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Fetch job statuses
+const (
+	JobPending = "pending" // Waiting for its run time or a free worker
+	JobRunning = "running" // Claimed by a worker
+	JobDone    = "done"    // Feed fetched successfully
+	JobDead    = "dead"    // All attempts failed, the job is not retried anymore
+)
+
+// FetchJob is a persisted request to fetch a feed
+type FetchJob struct {
+	ID          string
+	FeedID      string
+	FeedName    string
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LockedAt    *time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Feed *Feed // Feed to fetch, filled when the job is claimed
+}
@@ -2,14 +2,43 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
+// Supported feed document formats
+const (
+	FormatRSS  = "rss"  // RSS 2.0
+	FormatRDF  = "rdf"  // RSS 1.0 (RDF)
+	FormatAtom = "atom" // Atom 1.0
+	FormatJSON = "json" // JSON Feed 1.x
+)
+
 type RSSFeed struct {
 	ID        string
 	CreatedAt time.Time
+	Format    string  `xml:"-"`
 	Channel   Channel `xml:"channel"`
+
+	// HTTP cache validators returned with the document
+	ETag         string `xml:"-"`
+	LastModified string `xml:"-"`
+
+	Hints PublisherHints `xml:"-"`
+}
+
+// PublisherHints tell aggregators when the feed should not be fetched,
+// from <ttl>, <skipHours>, <skipDays> and sy:updatePeriod/sy:updateFrequency
+type PublisherHints struct {
+	TTL       time.Duration // Minimal time between fetches, 0 when not set
+	SkipHours []int         // GMT hours (0-23) when the feed should not be fetched
+	SkipDays  []string      // Week days (e.g. "Saturday") when the feed should not be fetched
+}
+
+// Equal reports whether both hints are the same
+func (h PublisherHints) Equal(other PublisherHints) bool {
+	return h.TTL == other.TTL && slices.Equal(h.SkipHours, other.SkipHours) && slices.Equal(h.SkipDays, other.SkipDays)
 }
 
 type Channel struct {
@@ -20,10 +49,42 @@ type Channel struct {
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	ID           string   `xml:"-"`
+	FeedName     string   `xml:"-"`    // Name of the stored feed, filled when articles are read from the database
+	GUID         string   `xml:"guid"` // Stable identity of the item within its feed
+	GUIDFallback bool     `xml:"-"`    // Feed gives no guid, GUID is derived from link and title
+	Title        string   `xml:"title"`
+	Link         string   `xml:"link"`
+	Description  string   `xml:"description"`
+	Content      string   `xml:"-"` // Full body in HTML, from content:encoded or Atom <content>
+	Author       string   `xml:"-"` // Comma separated author names
+	Categories   []string `xml:"-"` // Lower-cased category names
+	PubDate      string   `xml:"pubDate"`
+
+	PublishedAt    time.Time `xml:"-"` // Normalized PubDate, fetch time when the date is missing or unparseable
+	PubDateInvalid bool      `xml:"-"` // PubDate is present but could not be parsed
+
+	Enclosures []Enclosure `xml:"-"` // Media files attached to the item
+
+	Read    bool `xml:"-"` // Marked as read
+	Starred bool `xml:"-"`
+
+	New bool `xml:"-"` // Inserted, not updated, by the last CreateOrUpdate
+}
+
+// Kinds of article enclosures
+const (
+	EnclosureKindFile      = "enclosure" // <enclosure> or Atom link rel="enclosure"
+	EnclosureKindMedia     = "media"     // media:content
+	EnclosureKindThumbnail = "thumbnail" // media:thumbnail
+)
+
+// Enclosure represent media file attached to an article, e.g. podcast episode
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64 // Size in bytes, 0 when unknown
+	Kind   string
 }
 
 // To pretty print of RSSFeed struct
@@ -33,6 +94,7 @@ func (f RSSFeed) String() string {
 	sb.WriteString("RSS Feed:\n")
 	sb.WriteString(fmt.Sprintf("  ID: %s\n", f.ID))
 	sb.WriteString(fmt.Sprintf("  CreatedAt: %s\n", f.CreatedAt.Format(time.RFC1123)))
+	sb.WriteString(fmt.Sprintf("  Format: %s\n", f.Format))
 	sb.WriteString("  Channel:\n")
 	sb.WriteString(fmt.Sprintf("    Title: %s\n", f.Channel.Title))
 	sb.WriteString(fmt.Sprintf("    Link: %s\n", f.Channel.Link))
@@ -41,10 +103,18 @@ func (f RSSFeed) String() string {
 
 	for i, item := range f.Channel.Item {
 		sb.WriteString(fmt.Sprintf("      Item #%d:\n", i+1))
+		sb.WriteString(fmt.Sprintf("        GUID: %s\n", item.GUID))
 		sb.WriteString(fmt.Sprintf("        Title: %s\n", item.Title))
 		sb.WriteString(fmt.Sprintf("        Link: %s\n", item.Link))
 		sb.WriteString(fmt.Sprintf("        Description: %s\n", item.Description))
+		sb.WriteString(fmt.Sprintf("        Content: %s\n", item.Content))
+		sb.WriteString(fmt.Sprintf("        Author: %s\n", item.Author))
+		sb.WriteString(fmt.Sprintf("        Categories: %s\n", strings.Join(item.Categories, ", ")))
 		sb.WriteString(fmt.Sprintf("        PubDate: %s\n", item.PubDate))
+		sb.WriteString(fmt.Sprintf("        PublishedAt: %s\n", item.PublishedAt.Format(time.RFC3339)))
+		for _, e := range item.Enclosures {
+			sb.WriteString(fmt.Sprintf("        Enclosure: %s (%s, %s, %d bytes)\n", e.URL, e.Kind, e.Type, e.Length))
+		}
 	}
 
 	return sb.String()
@@ -56,6 +126,20 @@ type Feed struct {
 	Name        string
 	Description string
 	URL         string
+	Format      string
+	Interval    *time.Duration // Own fetch interval, nil means the global one is used
 	CreatedAt   time.Time
 	UpdatedAt   *time.Time
+
+	// HTTP cache validators of the last successful fetch, used for conditional requests
+	ETag         string
+	LastModified string
+
+	Hints PublisherHints // Publisher hints of the last successful fetch
+
+	// Health of the feed, failing feeds are fetched with exponential backoff
+	FailureCount int        // Consecutive failed fetches
+	LastError    string     // Error of the last failed fetch
+	NextFetchAt  *time.Time // Feed is not fetched before this time
+	Disabled     bool       // Feed is not fetched after too many failures
 }
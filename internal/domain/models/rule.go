@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Article fields matched by rules
+const (
+	RuleFieldTitle       = "title"
+	RuleFieldDescription = "description"
+	RuleFieldAuthor      = "author"
+	RuleFieldCategory    = "category" // Any category of the article
+)
+
+// Ways rules match their pattern, both are case insensitive
+const (
+	RuleMatchContains = "contains" // Field contains the pattern
+	RuleMatchRegex    = "regex"    // Field matches the regular expression
+)
+
+// Actions of rules on matched articles
+const (
+	RuleActionDrop     = "drop"      // Article is not stored, it is hidden when other subscribers keep it
+	RuleActionTag      = "tag"       // Tag is added to categories of the article for the owner of the rule
+	RuleActionStar     = "star"      // Article is starred for the owner of the rule
+	RuleActionMarkRead = "mark-read" // Article is marked as read for the owner of the rule
+)
+
+// Rule is evaluated on behalf of its owner on articles of fetched feeds, between fetching and storing them.
+// Rules are scoped to a feed, a group or all feeds the owner is subscribed to.
+type Rule struct {
+	ID        string
+	UserID    string
+	FeedName  string // Only articles of the feed, empty means any
+	Group     string // Only articles of feeds in the group, empty means any
+	Field     string
+	Match     string
+	Pattern   string
+	Action    string
+	Tag       string // Category added by tag rules
+	CreatedAt time.Time
+}
+
+// ArticleState is the state of an article for a user set by rules
+type ArticleState struct {
+	UserID    string
+	ArticleID string
+	Read      bool
+	Starred   bool
+	Hidden    bool     // Dropped by rules of the user
+	Tags      []string // Added by tag rules of the user, shown among categories of the article
+}
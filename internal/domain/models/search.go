@@ -0,0 +1,11 @@
+package models
+
+// SearchMark wraps the matched words in snippets of search results
+const SearchMark = "**"
+
+// SearchResult is an article found by full-text search
+type SearchResult struct {
+	Article *RSSItem
+	Rank    float64 // Relevance to the query, higher is better
+	Snippet string  // Fragments of the description with the matched words highlighted
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DefaultUser owns the data created before users existed, it is used when no user is given
+const DefaultUser = "default"
+
+// User is a person sharing the deployment with own subscriptions and article states
+type User struct {
+	ID        string
+	Name      string
+	HasToken  bool // API token is issued
+	Admin     bool // Manages fetching and config of the whole deployment
+	CreatedAt time.Time
+	Feeds     int // Number of subscribed feeds
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"RSSHub/internal/domain/models"
 	"context"
+	"io"
 	"time"
 )
 
@@ -10,17 +11,64 @@ type Aggregator interface {
 	// Core lifecycle
 	Start(ctx context.Context) error // Starts background feed polling
 	Stop() error                     // Graceful shutdown
+	Done() <-chan struct{}           // Closed when the background process stops
 
 	// Dynamic configuration
-	SetInterval(d time.Duration) error // Dynamically changes fetch interval
-	Resize(workers int) error          // Dynamically resizes worker pool
+	SetInterval(d time.Duration) error                                // Dynamically changes fetch interval
+	SetFeedInterval(user, feedName string, d time.Duration) error     // Changes fetch interval of one feed, 0 resets it to the global one, admins only
+	Resize(workers int) error                                         // Dynamically resizes worker pool
+	SetHostLimits(maxConcurrency int, minSpacing time.Duration) error // Changes per-host request limits
+	UpdateConfig(cfg *models.RssConfig) error                         // Validates and replaces the whole config at once
 	GetConfig(ctx context.Context) (*models.RssConfig, error)
+	GetStatus(ctx context.Context) (*models.AggregatorStatus, error) // Config with the current scheduling state
 
-	// Feed management
-	AddFeed(name, desc, url string) error      // Adds a new feed
-	DeleteFeed(name string) error              // Deletes feed by name
-	ListFeeds(num int) ([]*models.Feed, error) // Lists all feeds
+	// Users
+	AddUser(name string) (string, error)             // Creates a user, returns its API token
+	ResetToken(name string) (string, error)          // Issues a new API token of the user
+	SetAdmin(name string, admin bool) error          // Grants or revokes admin rights of the user
+	DeleteUser(name string) error                    // Deletes the user with its subscriptions
+	ListUsers() ([]*models.User, error)              // Lists all users
+	Authenticate(token string) (*models.User, error) // Gets the user owning the API token
+
+	// Feed management, scoped to the subscriptions of the user
+	AddFeed(user, name, desc, url string) (*models.Feed, error)              // Adds a new feed or subscribes to the existing one with the URL
+	DeleteFeed(user, name string) error                                      // Unsubscribes from the feed, deletes it without other subscribers
+	EnableFeed(user, name string) error                                      // Resets failures of the feed and enables it, admins only
+	ListFeeds(user string, filter models.FeedFilter) ([]*models.Feed, error) // Lists subscribed feeds, of the group when it is given
+	GetFeed(user, name string) (*models.Feed, error)                         // Gets subscribed feed by name
+
+	// Subscription lists
+	ImportOPML(user string, r io.Reader) ([]*models.ImportResult, error) // Subscribes to feeds of the OPML document, duplicates are skipped
+	ExportOPML(user string, w io.Writer) error                           // Writes subscribed feeds as OPML 2.0
+
+	// Groups of feeds
+	GetGroup(user, name string) (*models.Group, error)  // Gets group of the user by name
+	CreateGroup(user, name string) error                // Creates an empty group
+	AddGroupFeed(user, group, feedName string) error    // Adds a subscribed feed to the group
+	RemoveGroupFeed(user, group, feedName string) error // Removes the feed from the group, the subscription is kept
+	DeleteGroup(user, name string) error                // Deletes the group, its feeds are kept
+	ListGroups(user string) ([]*models.Group, error)    // Lists groups with their feeds
+
+	// Rules evaluated on fetched articles, scoped to the user
+	AddRule(user string, rule *models.Rule) (*models.Rule, error)      // Validates and creates the rule
+	ListRules(user string) ([]*models.Rule, error)                     // Lists rules of the user
+	DeleteRule(user, id string) error                                  // Deletes the rule by id
+	TestRule(user, id string) (*models.Rule, []*models.RSSItem, error) // Dry-runs the rule against stored articles, matched ones are returned
+
+	// Keyword alerts posted to webhooks, scoped to the user
+	AddAlert(user string, alert *models.Alert) (*models.Alert, error)                          // Validates and creates the alert, its signing secret is returned only here
+	ListAlerts(user string) ([]*models.Alert, error)                                           // Lists alerts of the user
+	DeleteAlert(user, id string) error                                                         // Deletes the alert by id with its deliveries
+	ListDeliveries(user string, filter models.DeliveryFilter) ([]*models.AlertDelivery, error) // Lists webhook deliveries matching the filter
+
+	// Fetch queue
+	ListJobs(filter models.JobFilter) ([]*models.FetchJob, error) // Lists fetch jobs matching the filter
 
 	// Article retrieval
-	GetArticles(feedName string, num int) ([]*models.RSSItem, error) // Gets latest 'num' articles for the feed
+	GetArticles(user string, filter models.ArticleFilter) ([]*models.RSSItem, error)        // Gets latest articles of the feed, group or all subscribed feeds matching the filter
+	SearchArticles(user string, filter models.SearchFilter) ([]*models.SearchResult, error) // Full-text search, the most relevant articles first
+
+	// Article state, kept per user
+	MarkRead(user, feedName string, before time.Time) (int64, error) // Marks articles of the feed published before the time as read
+	StarArticle(user, id string, starred bool) error                 // Stars or unstars the article
 }
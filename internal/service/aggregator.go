@@ -10,17 +10,58 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	// maxConsecutiveFailures is the number of failed fetches in a row after which the feed is disabled.
+	maxConsecutiveFailures = 10
+	// maxBackoff caps the delay before the next fetch of a failing feed.
+	maxBackoff = 24 * time.Hour
+
+	// maxJobAttempts is the number of attempts of a fetch job before it is moved to the dead-letter state.
+	maxJobAttempts = 5
+	// jobPollInterval is how often due jobs are claimed from the queue.
+	jobPollInterval = 2 * time.Second
+	// jobLockTimeout is the time after which a running job is considered abandoned and is requeued.
+	jobLockTimeout = 10 * time.Minute
+	// jobRetention is how long done jobs are kept.
+	jobRetention = 24 * time.Hour
+	// jobRecordTimeout limits recording the outcome of a job, it is recorded even when the controller is stopped.
+	jobRecordTimeout = 5 * time.Second
+
+	// heartbeatInterval is how often the running instance updates its heartbeat row.
+	heartbeatInterval = 5 * time.Second
+	// instanceStaleAfter is the time without a heartbeat after which an instance is considered dead.
+	instanceStaleAfter = 30 * time.Second
+	// leaseDuration is how long a feed stays claimed by an instance without renewal.
+	leaseDuration = time.Minute
+
+	// maxDeliveryAttempts is the number of attempts of an alert delivery before it fails.
+	maxDeliveryAttempts = 6
+	// deliveryBaseBackoff is the delay after the first failed attempt of a delivery, it doubles with every attempt.
+	deliveryBaseBackoff = 30 * time.Second
+	// maxDeliveryBackoff caps the delay before the next attempt of a delivery.
+	maxDeliveryBackoff = time.Hour
+	// deliveryPollInterval is how often due deliveries are claimed from the queue.
+	deliveryPollInterval = 2 * time.Second
+	// deliveryBatchSize is the maximum of deliveries posted at once.
+	deliveryBatchSize = 20
+	// deliveryLockTimeout is the time after which a running delivery is considered abandoned and is requeued.
+	deliveryLockTimeout = 5 * time.Minute
+	// webhookTimeout limits a single webhook request.
+	webhookTimeout = 10 * time.Second
+	// deliveryRecordTimeout limits recording the result of a delivery, it is recorded even during shutdown.
+	deliveryRecordTimeout = 5 * time.Second
+)
+
 var (
-	ErrConfigNotFound        = errors.New("RSS config not found")
-	ErrProcessAlreadyRunning = errors.New("background process already running")
-	ErrFailedToReadConfig    = errors.New("failed to read config")
-	ErrFailedToUpdateStatus  = errors.New("failed to update aggregator status")
+	ErrConfigNotFound     = errors.New("RSS config not found")
+	ErrFailedToReadConfig = errors.New("failed to read config")
+	ErrFailedToLock       = errors.New("failed to take instance lock")
+	ErrFailedToRegister   = errors.New("failed to register aggregator instance")
 )
 
 // RssAggregator is the main service that manages the RSS feed aggregation process.
@@ -29,36 +70,57 @@ type RssAggregator struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
+	mu     sync.Mutex // Serializes Start and Stop
 
-	log     logger.Logger
-	cleanDb func()
+	log logger.Logger
 
-	articleRepo *repo.ArticleRepo
-	feedRepo    *repo.FeedRepo
-	configRepo  *repo.ConfigRepo
+	articleRepo  *repo.ArticleRepo
+	feedRepo     *repo.FeedRepo
+	configRepo   *repo.ConfigRepo
+	jobRepo      *repo.JobRepo
+	instanceRepo *repo.InstanceRepo
+	groupRepo    *repo.GroupRepo
+	userRepo     *repo.UserRepo
+	ruleRepo     *repo.RuleRepo
+	alertRepo    *repo.AlertRepo
+
+	lock       *repo.AdvisoryLock // Lock of the instance, held while the aggregator is running
+	instanceID string             // Id of the running instance, owner of its feed leases
+	lockLost   chan struct{}      // Closed when the session holding the lock is lost
+	done       chan struct{}      // Closed when the background process is stopped
 
 	tc *TickerController
 	wc *WorkerController
 }
 
-func NewRssAggregator(articleRepo *repo.ArticleRepo, feedRepo *repo.FeedRepo, configRepo *repo.ConfigRepo, log logger.Logger, cleanDb func()) *RssAggregator {
-	ctx, cancel := context.WithCancel(context.Background())
+func NewRssAggregator(articleRepo *repo.ArticleRepo, feedRepo *repo.FeedRepo, configRepo *repo.ConfigRepo, jobRepo *repo.JobRepo, instanceRepo *repo.InstanceRepo, groupRepo *repo.GroupRepo, userRepo *repo.UserRepo, ruleRepo *repo.RuleRepo, alertRepo *repo.AlertRepo, log logger.Logger) *RssAggregator {
 	return &RssAggregator{
-		ctx:         ctx,
-		cancel:      cancel,
-		cleanDb:     cleanDb,
-		log:         log,
-		articleRepo: articleRepo,
-		feedRepo:    feedRepo,
-		configRepo:  configRepo,
+		log:          log,
+		articleRepo:  articleRepo,
+		feedRepo:     feedRepo,
+		configRepo:   configRepo,
+		jobRepo:      jobRepo,
+		instanceRepo: instanceRepo,
+		groupRepo:    groupRepo,
+		userRepo:     userRepo,
+		ruleRepo:     ruleRepo,
+		alertRepo:    alertRepo,
 	}
 }
 
 // Start launches the RSS aggregator, ticker controller, and worker controller with the configuration parameters.
+// It returns once the background process is running, Done is closed when it stops.
 func (a *RssAggregator) Start(ctx context.Context) error {
 	const op = "RssAggregator.Start"
 	log := a.log.GetSlogLogger().With("op", op)
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.lock != nil {
+		return models.ErrAlreadyRunning
+	}
+
 	// Reading configuration
 	cfg, err := a.GetConfig(ctx)
 	if err != nil {
@@ -66,48 +128,91 @@ func (a *RssAggregator) Start(ctx context.Context) error {
 		return err
 	}
 
-	// Checking if process is already runnning
-	if cfg.Run {
-		return ErrProcessAlreadyRunning
+	// Several instances may run at once, each one holds its own lock which is released with its session,
+	// so the instance is known to be dead as soon as the process is killed
+	lock, err := a.instanceRepo.Lock(ctx)
+	if err != nil {
+		log.Error("Failed to take instance lock", "error", err)
+		return ErrFailedToLock
 	}
 
-	// Updating application status
-	if err := a.configRepo.UpdateRunStatus(ctx, true); err != nil {
-		log.Error("Failed to update aggregator status", "error", err)
-		return ErrFailedToUpdateStatus
+	if err := a.register(ctx, lock.ID()); err != nil {
+		log.Error("Failed to register aggregator instance", "error", err)
+		if err := lock.Release(context.Background()); err != nil {
+			log.Error("Failed to release instance lock", "error", err)
+		}
+		return ErrFailedToRegister
 	}
+	a.lock = lock
 
-	a.wc = NewWorkerController(cfg.WorkerCount, a.log)
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+	a.lockLost = make(chan struct{})
+	a.done = make(chan struct{})
+
+	a.wc = NewWorkerController(cfg.WorkerCount, NewHostLimiter(cfg.HostMaxConcurrency, cfg.HostMinSpacing), a.log)
 
 	// Initialize rss fetcher.
 	rssFetcher := httpadapter.NewClient(time.Second * 5)
-	a.tc = NewTickerController(cfg.TimerInterval, a.feedRepo, a.articleRepo, rssFetcher, a.log)
+	a.tc = NewTickerController(cfg.TimerInterval, a.instanceID, a.feedRepo, a.articleRepo, a.jobRepo, a.ruleRepo, a.alertRepo, rssFetcher, a.log)
 
 	a.wg.Add(2)
 	go a.tc.Run(a.ctx, &a.wg, a.wc)
 	go a.wc.Run(a.ctx, cfg.WorkerCount, &a.wg)
 
-	a.wg.Add(2)
-	go a.intervalUpdater(a.ctx, cfg.TimerInterval)
+	a.wg.Add(5)
+	go a.heartbeat(a.ctx)
+	go a.alertDispatcher(a.ctx, httpadapter.NewWebhookClient(webhookTimeout))
+	go a.intervalUpdater(a.ctx, newSchedule(cfg.TimerInterval, nil))
 	go a.countUpdater(a.ctx, cfg.WorkerCount)
+	go a.hostLimitUpdater(a.ctx, cfg.HostMaxConcurrency, cfg.HostMinSpacing)
+
+	// Instance without its lock is dead for the others, so it stops by itself
+	go func(lockLost, done <-chan struct{}) {
+		select {
+		case <-lockLost:
+			a.log.Notify("Instance lock has been lost, stopping the aggregator")
+			if err := a.Stop(); err != nil && !errors.Is(err, models.ErrNotRunning) {
+				a.log.Error(context.Background(), "Failed to stop aggregator", "error", err)
+			}
+		case <-done:
+		}
+	}(a.lockLost, a.done)
 
 	msg := fmt.Sprintf("The background process for fetching feeds has started (interval = %s, workers = %d)", utils.PrettyDuration(cfg.TimerInterval), cfg.WorkerCount)
 	a.log.Notify(msg)
-	a.ListenShutdown(a.ctx)
 	return nil
 }
 
-// Stop gracefully shuts down the RSS aggregator and updates the run status in the repository.
+// Stop gracefully shuts down the RSS aggregator, removes its heartbeat row and releases the lock.
 func (a *RssAggregator) Stop() error {
 	const op = "RssAggregator.Stop"
 	log := a.log.GetSlogLogger().With("op", op)
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.lock == nil {
+		return models.ErrNotRunning
+	}
+
 	a.cancel()
 	a.wg.Wait()
 
-	if err := a.configRepo.UpdateRunStatus(context.Background(), false); err != nil {
-		log.Error("Failed to change app status", "error", err)
-		return fmt.Errorf("%s:%w", op, ErrFailedToUpdateStatus)
+	lock := a.lock
+	a.lock = nil
+	defer close(a.done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	// Leases of the instance are released with its row
+	if err := a.instanceRepo.Unregister(ctx, a.instanceID); err != nil {
+		log.Error("Failed to unregister aggregator instance", "error", err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		log.Error("Failed to release aggregator lock", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	msg := "Graceful shutdown: aggregator stopped"
@@ -115,58 +220,143 @@ func (a *RssAggregator) Stop() error {
 	return nil
 }
 
+// Done returns a channel closed when the background process started by the last Start is stopped,
+// either by Stop or by itself.
+func (a *RssAggregator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.done
+}
+
+// register removes heartbeat rows of dead instances, releasing their leases, and creates the row of this one.
+func (a *RssAggregator) register(ctx context.Context, id string) error {
+	if err := a.instanceRepo.DeleteStale(ctx, instanceStaleAfter); err != nil {
+		return err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	if err := a.instanceRepo.Register(ctx, id, host, os.Getpid()); err != nil {
+		return err
+	}
+	a.instanceID = id
+
+	return nil
+}
+
 // ---------------- TickerController ----------------
 
 type RssFetcher interface {
-	FetchRSSFeed(ctx context.Context, url string) (*models.RSSFeed, error)
+	FetchRSSFeed(ctx context.Context, feed *models.Feed) (*models.RSSFeed, error)
+}
+
+type WebhookSender interface {
+	SendWebhook(ctx context.Context, delivery *models.AlertDelivery) (int, error)
+}
+
+// schedule describes how often feeds are fetched.
+type schedule struct {
+	interval time.Duration // Global fetch interval, default for feeds without their own one
+	tick     time.Duration // Ticker period, the shortest of the global and per-feed intervals
+}
+
+func newSchedule(interval time.Duration, minFeedInterval *time.Duration) schedule {
+	s := schedule{interval: interval, tick: interval}
+	if minFeedInterval != nil && *minFeedInterval > 0 && *minFeedInterval < s.tick {
+		s.tick = *minFeedInterval
+	}
+	return s
 }
 
 type TickerController struct {
 	t           *VarTicker
-	intervalCh  chan time.Duration
+	interval    time.Duration
+	instanceID  string
+	intervalCh  chan schedule
 	feedRepo    *repo.FeedRepo
 	articleRepo *repo.ArticleRepo
+	jobRepo     *repo.JobRepo
+	ruleRepo    *repo.RuleRepo
+	alertRepo   *repo.AlertRepo
 	rssFethcer  RssFetcher
 	log         logger.Logger
 }
 
-func NewTickerController(interval time.Duration, feedRepo *repo.FeedRepo, articleRepo *repo.ArticleRepo, rssFethcer RssFetcher, log logger.Logger) *TickerController {
+func NewTickerController(interval time.Duration, instanceID string, feedRepo *repo.FeedRepo, articleRepo *repo.ArticleRepo, jobRepo *repo.JobRepo, ruleRepo *repo.RuleRepo, alertRepo *repo.AlertRepo, rssFethcer RssFetcher, log logger.Logger) *TickerController {
 	return &TickerController{
 		t:           NewVarTicker(interval),
-		intervalCh:  make(chan time.Duration, 1),
+		interval:    interval,
+		instanceID:  instanceID,
+		intervalCh:  make(chan schedule, 1),
 		feedRepo:    feedRepo,
 		articleRepo: articleRepo,
+		jobRepo:     jobRepo,
+		ruleRepo:    ruleRepo,
+		alertRepo:   alertRepo,
 		rssFethcer:  rssFethcer,
 		log:         log,
 	}
 }
 
-// Starts the ticker loop, periodically fetching stale feeds and dispatching jobs to the worker controller.
+// Starts the ticker loop, periodically enqueuing fetch jobs for stale feeds
+// and dispatching due jobs from the queue to the worker controller.
 func (c *TickerController) Run(ctx context.Context, wg *sync.WaitGroup, wc *WorkerController) {
 	defer wg.Done()
 	defer c.t.Stop()
 	defer close(wc.wp.jobCh)
 
+	poll := time.NewTicker(jobPollInterval)
+	defer poll.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			c.log.Debug(ctx, "ticker controller has been stopped")
 			return
 		case <-c.t.ticker.C:
-			c.processFeeds(ctx, wc)
-		case newInterval := <-c.intervalCh:
-			oldInterval := c.t.GetDuration()
-			c.t.Reset(newInterval)
+			c.processFeeds(ctx)
+			c.dispatchJobs(ctx, wc)
+		case <-poll.C:
+			c.dispatchJobs(ctx, wc)
+		case s, ok := <-c.intervalCh:
+			if !ok {
+				// Updater has been stopped, waiting for the context cancellation
+				c.intervalCh = nil
+				continue
+			}
+
+			if s.tick != c.t.GetDuration() {
+				c.t.Reset(s.tick)
+				c.log.Debug(ctx, "ticker period changed", "period", s.tick)
+			}
 
-			msg := fmt.Sprintf("Interval of fetching feeds changed from %s to %s", utils.PrettyDuration(oldInterval), utils.PrettyDuration(newInterval))
-			c.log.Notify(msg)
+			if s.interval != c.interval {
+				msg := fmt.Sprintf("Interval of fetching feeds changed from %s to %s", utils.PrettyDuration(c.interval), utils.PrettyDuration(s.interval))
+				c.interval = s.interval
+				c.log.Notify(msg)
+			}
 		}
 	}
 }
 
-// processFeeds retrieves stale feeds and submits jobs for each feed to fetch and save RSS items.
-func (c *TickerController) processFeeds(ctx context.Context, wc *WorkerController) {
-	feeds, err := c.feedRepo.GetStaleFeeds(ctx, c.t.GetDuration())
+// processFeeds retrieves stale feeds and enqueues a fetch job for each of them,
+// jobs abandoned by crashed workers are requeued and old done jobs are purged.
+func (c *TickerController) processFeeds(ctx context.Context) {
+	if n, err := c.jobRepo.RequeueStuck(ctx, jobLockTimeout); err != nil {
+		c.log.Error(ctx, "Failed to requeue stuck jobs", "error", err)
+	} else if n > 0 {
+		c.log.Warn(ctx, "Requeued stuck fetch jobs", "count", n)
+	}
+
+	if err := c.jobRepo.PurgeDone(ctx, jobRetention); err != nil {
+		c.log.Error(ctx, "Failed to purge done jobs", "error", err)
+	}
+
+	feeds, err := c.feedRepo.GetStaleFeeds(ctx, c.interval, c.instanceID, leaseDuration)
 	if err != nil {
 		c.log.Error(ctx, "Failed to get stale feeds", "error", err)
 		return
@@ -175,43 +365,368 @@ func (c *TickerController) processFeeds(ctx context.Context, wc *WorkerControlle
 		c.log.Error(ctx, "feeds list is empty")
 		return
 	}
+
+	ids := make([]string, 0, len(feeds))
 	for _, feed := range feeds {
-		wc.SubmitJob(func() {
-			fetched, err := c.rssFethcer.FetchRSSFeed(ctx, feed.URL)
-			if err != nil {
-				c.log.Error(ctx, "Failed to fetch RSS feed", "feed_URL", feed.URL, "error", err)
-				return
-			}
-			if len(fetched.Channel.Item) == 0 {
-				c.log.Error(ctx, "There is no items in the feed", "feed_URL", feed.URL)
-				return
-			}
+		ids = append(ids, feed.ID)
+	}
 
-			if err := c.articleRepo.CreateOrUpdate(ctx, feed.ID, fetched.Channel.Item); err != nil {
-				c.log.Error(ctx, "Failed to save feed items", "feed_id", feed.ID, "articles", fetched.Channel.Item, "error", err)
-				return
-			}
+	n, err := c.jobRepo.Enqueue(ctx, ids, maxJobAttempts)
+	if err != nil {
+		c.log.Error(ctx, "Failed to enqueue fetch jobs", "error", err)
+		return
+	}
+	c.log.Debug(ctx, "fetch jobs enqueued", "stale feeds", len(feeds), "jobs", n)
+}
 
-			if err := c.feedRepo.UpdateUpdatedAt(ctx, feed.Name); err != nil {
-				c.log.Error(ctx, "Failed to update updated_at", "error", err)
-				return
-			}
+// dispatchJobs claims as many due jobs as there are idle workers and submits them to the worker controller.
+// Jobs of hosts which per-host limits don't allow right now are returned to the queue until they may start,
+// so workers never wait for a busy host while jobs of other hosts are queued.
+func (c *TickerController) dispatchJobs(ctx context.Context, wc *WorkerController) {
+	idle := wc.Idle()
+	if idle <= 0 {
+		return
+	}
+
+	jobs, err := c.jobRepo.Claim(ctx, idle, c.instanceID, leaseDuration)
+	if err != nil {
+		c.log.Error(ctx, "Failed to claim fetch jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		host := hostOf(job.Feed.URL)
+		release, retryAt, ok := wc.limiter.TryAcquire(host)
+		if !ok {
+			c.postponeJob(ctx, job, retryAt)
+			continue
+		}
+
+		wc.SubmitJob(release, func() {
+			c.runJob(ctx, wc, host, job)
 		})
 	}
+}
+
+// runJob fetches the feed of the job and records the outcome of the attempt on the job and the feed,
+// the lease of the feed is released afterwards. A fetch cut short by a stop of the controller
+// is neither an attempt of the job nor a failure of the feed, the job is returned to the queue.
+func (c *TickerController) runJob(ctx context.Context, wc *WorkerController, host string, job *models.FetchJob) {
+	err := c.fetchFeed(ctx, job.Feed)
+	if err != nil && ctx.Err() != nil {
+		c.postponeJob(ctx, job, time.Now())
+		return
+	}
+
+	// The outcome is recorded after a stop too, otherwise the job stays running until its lease expires
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobRecordTimeout)
+	defer cancel()
+	defer c.releaseLease(ctx, job.Feed)
+
+	var retryErr *httpadapter.RetryAfterError
+	switch {
+	case errors.As(err, &retryErr):
+		c.postponeFetch(ctx, wc, host, job.Feed, retryErr)
+		if err := c.jobRepo.Postpone(ctx, job.ID, retryErr.Until); err != nil {
+			c.log.Error(ctx, "Failed to postpone fetch job", "job_id", job.ID, "error", err)
+		}
+	case err != nil:
+		nextFetchAt, disabled := c.recordFailure(ctx, job.Feed, err)
+		c.retryJob(ctx, job, err, nextFetchAt, disabled)
+	default:
+		c.recordSuccess(ctx, job.Feed)
+		if err := c.jobRepo.Complete(ctx, job.ID); err != nil {
+			c.log.Error(ctx, "Failed to complete fetch job", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// postponeJob returns the claimed job to the queue until runAt without using up an attempt and releases the lease of its feed,
+// it is recorded when ctx is cancelled too.
+func (c *TickerController) postponeJob(ctx context.Context, job *models.FetchJob, runAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobRecordTimeout)
+	defer cancel()
+
+	if err := c.jobRepo.Postpone(ctx, job.ID, runAt); err != nil {
+		c.log.Error(ctx, "Failed to postpone fetch job", "job_id", job.ID, "error", err)
+	}
+	c.releaseLease(ctx, job.Feed)
+}
+
+// releaseLease frees the lease of the instance on the feed, so the next job of the feed may run anywhere.
+func (c *TickerController) releaseLease(ctx context.Context, feed *models.Feed) {
+	if err := c.feedRepo.ReleaseLease(ctx, feed.ID, c.instanceID); err != nil {
+		c.log.Error(ctx, "Failed to release feed lease", "feed_id", feed.ID, "error", err)
+	}
+}
+
+// retryJob schedules the failed job to the next fetch time of its feed,
+// the job is moved to the dead-letter state when its attempts are exhausted or the feed has been disabled.
+func (c *TickerController) retryJob(ctx context.Context, job *models.FetchJob, fetchErr error, runAt time.Time, feedDisabled bool) {
+	if feedDisabled {
+		if err := c.jobRepo.Bury(ctx, job.ID, fetchErr.Error()); err != nil {
+			c.log.Error(ctx, "Failed to bury fetch job", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	dead, err := c.jobRepo.Retry(ctx, job.ID, fetchErr.Error(), runAt)
+	if err != nil {
+		c.log.Error(ctx, "Failed to schedule fetch job retry", "job_id", job.ID, "error", err)
+		return
+	}
+
+	if dead {
+		c.log.Warn(ctx, "Fetch job moved to dead letter", "job_id", job.ID, "feed_name", job.FeedName, "attempts", job.Attempts, "error", fetchErr)
+	}
+}
+
+// fetchFeed downloads the feed and stores its articles.
+func (c *TickerController) fetchFeed(ctx context.Context, feed *models.Feed) error {
+	fetched, err := c.rssFethcer.FetchRSSFeed(ctx, feed)
+	if errors.Is(err, httpadapter.ErrNotModified) {
+		c.log.Debug(ctx, "Feed is not modified since last fetch", "feed_URL", feed.URL)
+		if err := c.feedRepo.UpdateUpdatedAt(ctx, feed.ID); err != nil {
+			c.log.Error(ctx, "Failed to update updated_at", "error", err)
+		}
+		return nil
+	}
+	if err != nil {
+		c.log.Error(ctx, "Failed to fetch RSS feed", "feed_URL", feed.URL, "error", err)
+		return err
+	}
+	if fetched.Format != feed.Format {
+		if err := c.feedRepo.UpdateFormat(ctx, feed.ID, fetched.Format); err != nil {
+			c.log.Error(ctx, "Failed to update feed format", "feed_id", feed.ID, "error", err)
+		}
+	}
+	if !fetched.Hints.Equal(feed.Hints) {
+		if err := c.feedRepo.UpdateHints(ctx, feed.ID, fetched.Hints); err != nil {
+			c.log.Error(ctx, "Failed to update publisher hints", "feed_id", feed.ID, "error", err)
+		}
+	}
+
+	if len(fetched.Channel.Item) == 0 {
+		// Feed is reachable but empty, it is not a failure
+		c.log.Warn(ctx, "There is no items in the feed", "feed_URL", feed.URL)
+	} else {
+		c.reportInvalidDates(ctx, feed, fetched.Channel.Item)
+
+		if err := c.storeArticles(ctx, feed, fetched.Channel.Item); err != nil {
+			return err
+		}
+	}
+
+	if err := c.feedRepo.UpdateUpdatedAt(ctx, feed.ID); err != nil {
+		c.log.Error(ctx, "Failed to update updated_at", "error", err)
+		return nil
+	}
+
+	if fetched.ETag != feed.ETag || fetched.LastModified != feed.LastModified {
+		if err := c.feedRepo.UpdateValidators(ctx, feed.ID, fetched.ETag, fetched.LastModified); err != nil {
+			c.log.Error(ctx, "Failed to update cache validators", "feed_id", feed.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// storeArticles evaluates rules of the feed subscribers on the fetched articles and stores the articles they keep,
+// states set by the rules are applied to newly inserted articles only, so changes of the users are not overridden.
+// Alerts are evaluated on newly inserted articles as well.
+func (c *TickerController) storeArticles(ctx context.Context, feed *models.Feed, articles []models.RSSItem) error {
+	outcome := c.applyRules(ctx, feed, articles)
+	if outcome.dropped > 0 {
+		c.log.Debug(ctx, "Articles dropped by rules", "feed_name", feed.Name, "count", outcome.dropped)
+	}
+	if len(outcome.kept) == 0 {
+		return nil
+	}
+
+	if err := c.articleRepo.CreateOrUpdate(ctx, feed.ID, outcome.kept); err != nil {
+		c.log.Error(ctx, "Failed to save feed items", "feed_id", feed.ID, "articles", outcome.kept, "error", err)
+		return errors.New("failed to save feed items")
+	}
+
+	var states []models.ArticleState
+	for i, article := range outcome.kept {
+		if !article.New {
+			continue
+		}
+		for _, state := range outcome.states[i] {
+			state.ArticleID = article.ID
+			states = append(states, state)
+		}
+	}
+
+	// Articles are stored, failing to apply states is not a failure of the feed
+	if err := c.articleRepo.SetStates(ctx, states); err != nil {
+		c.log.Error(ctx, "Failed to apply states set by rules", "feed_id", feed.ID, "error", err)
+	}
 
+	c.enqueueAlerts(ctx, feed, outcome)
+	return nil
+}
+
+// enqueueAlerts matches newly inserted articles against alerts of the feed subscribers and enqueues their deliveries,
+// articles hidden from the owner of an alert by rules are not delivered.
+func (c *TickerController) enqueueAlerts(ctx context.Context, feed *models.Feed, outcome ruleOutcome) {
+	alerts, err := c.alertRepo.ListForFeed(ctx, feed.ID)
+	if err != nil {
+		c.log.Error(ctx, "Failed to get alerts of the feed", "feed_id", feed.ID, "error", err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	matchers := make([]*alertMatcher, 0, len(alerts))
+	for _, alert := range alerts {
+		m, err := compileAlert(alert)
+		if err != nil {
+			c.log.Warn(ctx, "Skipping invalid alert", "alert_id", alert.ID, "error", err)
+			continue
+		}
+		matchers = append(matchers, m)
+	}
+
+	var alertIDs, articleIDs []string
+	for i, article := range outcome.kept {
+		if !article.New {
+			continue
+		}
+		for _, m := range matchers {
+			if hiddenFrom(outcome.states[i], m.UserID) || !m.matches(&article) {
+				continue
+			}
+			alertIDs = append(alertIDs, m.ID)
+			articleIDs = append(articleIDs, article.ID)
+		}
+	}
+	if len(alertIDs) == 0 {
+		return
+	}
+
+	n, err := c.alertRepo.Enqueue(ctx, alertIDs, articleIDs, maxDeliveryAttempts)
+	if err != nil {
+		c.log.Error(ctx, "Failed to enqueue alert deliveries", "feed_id", feed.ID, "error", err)
+		return
+	}
+	c.log.Debug(ctx, "alert deliveries enqueued", "feed_name", feed.Name, "deliveries", n)
+}
+
+// hiddenFrom reports whether the states hide the article from the user
+func hiddenFrom(states []models.ArticleState, userID string) bool {
+	for _, state := range states {
+		if state.UserID == userID && state.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules loads rules applying to the feed and evaluates them on the articles,
+// the articles are kept as they are when rules can't be loaded.
+func (c *TickerController) applyRules(ctx context.Context, feed *models.Feed, articles []models.RSSItem) ruleOutcome {
+	keepAll := ruleOutcome{kept: articles, states: make([][]models.ArticleState, len(articles))}
+
+	rules, err := c.ruleRepo.ListForFeed(ctx, feed.ID)
+	if err != nil {
+		c.log.Error(ctx, "Failed to get rules of the feed", "feed_id", feed.ID, "error", err)
+		return keepAll
+	}
+	if len(rules) == 0 {
+		return keepAll
+	}
+
+	subscribers, err := c.feedRepo.CountSubscribers(ctx, feed.ID)
+	if err != nil {
+		c.log.Error(ctx, "Failed to count feed subscribers", "feed_id", feed.ID, "error", err)
+		return keepAll
+	}
+
+	matchers := make([]*ruleMatcher, 0, len(rules))
+	for _, rule := range rules {
+		m, err := compileRule(rule)
+		if err != nil {
+			c.log.Warn(ctx, "Skipping invalid rule", "rule_id", rule.ID, "error", err)
+			continue
+		}
+		matchers = append(matchers, m)
+	}
+
+	return applyRules(matchers, subscribers, articles)
+}
+
+// recordFailure stores the error of the feed and postpones its next fetch with exponential backoff,
+// the feed is disabled after maxConsecutiveFailures failures in a row. The time of the next fetch and the disabled state are returned.
+func (c *TickerController) recordFailure(ctx context.Context, feed *models.Feed, fetchErr error) (time.Time, bool) {
+	nextFetchAt, disabled, err := c.feedRepo.RecordFailure(ctx, feed.ID, fetchErr.Error(), c.interval, maxBackoff, maxConsecutiveFailures)
+	if err != nil {
+		c.log.Error(ctx, "Failed to record feed failure", "feed_id", feed.ID, "error", err)
+		return time.Now().Add(c.interval), false
+	}
+
+	if disabled {
+		msg := fmt.Sprintf("Feed %s has been disabled after %d failed fetches in a row, last error: %s", feed.Name, maxConsecutiveFailures, fetchErr)
+		c.log.Notify(msg)
+	}
+
+	return nextFetchAt, disabled
+}
+
+// postponeFetch holds back requests to the host and the next fetch of the feed until the time asked by the server,
+// being rate limited is not counted as a failure of the feed.
+func (c *TickerController) postponeFetch(ctx context.Context, wc *WorkerController, host string, feed *models.Feed, retryErr *httpadapter.RetryAfterError) {
+	wc.limiter.Defer(host, retryErr.Until)
+	c.log.Warn(ctx, "Host asked to retry later", "host", host, "feed_name", feed.Name, "status", retryErr.StatusCode, "until", retryErr.Until)
+
+	if err := c.feedRepo.PostponeFetch(ctx, feed.ID, retryErr.Until); err != nil {
+		c.log.Error(ctx, "Failed to postpone feed fetch", "feed_id", feed.ID, "error", err)
+	}
+}
+
+// recordSuccess resets the failure state of the feed.
+func (c *TickerController) recordSuccess(ctx context.Context, feed *models.Feed) {
+	if feed.FailureCount == 0 {
+		return
+	}
+
+	if err := c.feedRepo.ResetFailures(ctx, feed.ID); err != nil {
+		c.log.Error(ctx, "Failed to reset feed failures", "feed_id", feed.ID, "error", err)
+	}
+}
+
+// reportInvalidDates logs items whose publication date could not be parsed and was replaced by the fetch time.
+func (c *TickerController) reportInvalidDates(ctx context.Context, feed *models.Feed, items []models.RSSItem) {
+	var invalid []string
+	for _, item := range items {
+		if item.PubDateInvalid {
+			invalid = append(invalid, fmt.Sprintf("%q (%s)", item.PubDate, item.Link))
+		}
+	}
+
+	if len(invalid) != 0 {
+		c.log.Warn(ctx, "Unparseable publication dates replaced by fetch time", "feed_name", feed.Name, "count", len(invalid), "items", invalid)
+	}
 }
 
 // ---------------- WorkerController -----------------
 
 type WorkerController struct {
 	wp      *WorkerParty
+	limiter *HostLimiter
 	countCh chan int
 	log     logger.Logger
+
+	size     atomic.Int64 // Current number of workers
+	inFlight atomic.Int64 // Submitted jobs which are not finished yet
 }
 
-func NewWorkerController(initialCount int, log logger.Logger) *WorkerController {
+func NewWorkerController(initialCount int, limiter *HostLimiter, log logger.Logger) *WorkerController {
 	wc := &WorkerController{
 		wp:      NewWorkerParty(),
+		limiter: limiter,
 		countCh: make(chan int),
 		log:     log,
 	}
@@ -223,6 +738,7 @@ func (wc *WorkerController) Run(ctx context.Context, initCount int, wg *sync.Wai
 	defer wg.Done()
 	go wc.wp.Start(ctx)
 	wc.wp.Scale(initCount)
+	wc.size.Store(int64(initCount))
 
 	for {
 		select {
@@ -231,18 +747,135 @@ func (wc *WorkerController) Run(ctx context.Context, initCount int, wg *sync.Wai
 			return
 		case newCount := <-wc.countCh:
 			wc.wp.Scale(newCount)
+			wc.size.Store(int64(newCount))
 		}
 	}
 }
 
-func (wc *WorkerController) SubmitJob(job func()) {
-	wc.wp.jobCh <- job
+// SubmitJob dispatches the job to the workers, release frees the per-host slot acquired for the job once it is finished.
+func (wc *WorkerController) SubmitJob(release func(), job func()) {
+	wc.inFlight.Add(1)
+	wc.wp.jobCh <- func() {
+		defer wc.inFlight.Add(-1)
+		defer release()
+		job()
+	}
+}
+
+// Idle returns the number of workers which are not busy with submitted jobs.
+func (wc *WorkerController) Idle() int {
+	return int(wc.size.Load() - wc.inFlight.Load())
+}
+
+// ---------------- AlertDispatcher ----------------
+
+// alertDispatcher periodically claims due alert deliveries and posts them to their webhooks,
+// deliveries abandoned by crashed instances are requeued.
+func (a *RssAggregator) alertDispatcher(ctx context.Context, sender WebhookSender) {
+	defer a.wg.Done()
+
+	t := time.NewTicker(deliveryPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Debug(ctx, "alert dispatcher has been stopped")
+			return
+		case <-t.C:
+			a.dispatchDeliveries(ctx, sender)
+		}
+	}
+}
+
+// dispatchDeliveries posts a batch of due deliveries at once and waits for all of them.
+func (a *RssAggregator) dispatchDeliveries(ctx context.Context, sender WebhookSender) {
+	if n, err := a.alertRepo.RequeueStuck(ctx, deliveryLockTimeout); err != nil {
+		a.log.Error(ctx, "Failed to requeue stuck deliveries", "error", err)
+	} else if n > 0 {
+		a.log.Warn(ctx, "Requeued stuck alert deliveries", "count", n)
+	}
+
+	deliveries, err := a.alertRepo.Claim(ctx, deliveryBatchSize)
+	if err != nil {
+		a.log.Error(ctx, "Failed to claim alert deliveries", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.deliver(ctx, sender, delivery)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver posts the delivery and records the outcome of the attempt,
+// failed attempts are retried with exponential backoff until maxDeliveryAttempts are used.
+func (a *RssAggregator) deliver(ctx context.Context, sender WebhookSender, delivery *models.AlertDelivery) {
+	status, sendErr := sender.SendWebhook(ctx, delivery)
+
+	// The webhook may have received the alert already, so the result is recorded when ctx is cancelled too
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryRecordTimeout)
+	defer cancel()
+
+	if sendErr == nil {
+		if err := a.alertRepo.Complete(ctx, delivery.ID, status); err != nil {
+			a.log.Error(ctx, "Failed to complete alert delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(deliveryBackoff(delivery.Attempts))
+	failed, err := a.alertRepo.Retry(ctx, delivery.ID, status, sendErr.Error(), runAt)
+	if err != nil {
+		a.log.Error(ctx, "Failed to schedule alert delivery retry", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+
+	if failed {
+		a.log.Warn(ctx, "Alert delivery failed", "delivery_id", delivery.ID, "alert_id", delivery.AlertID, "attempts", delivery.Attempts, "error", sendErr)
+	}
 }
 
 // ---------------- Updaters ----------------
 
-// intervalUpdater periodically checks for updated timer intervals in the configuration and updates the ticker if needed.
-func (a *RssAggregator) intervalUpdater(ctx context.Context, currentInterval time.Duration) {
+// heartbeat periodically updates the heartbeat row of the instance, renews its feed leases
+// and checks the session holding the lock. Losing the session makes the instance dead for the others,
+// so the aggregator is shut down.
+func (a *RssAggregator) heartbeat(ctx context.Context) {
+	defer a.wg.Done()
+
+	t := time.NewTicker(heartbeatInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Debug(ctx, "heartbeat has been stopped")
+			return
+		case <-t.C:
+			if err := a.lock.Alive(ctx); err != nil {
+				a.log.Error(ctx, "Lost the session holding aggregator lock", "error", err)
+				close(a.lockLost)
+				return
+			}
+			if err := a.instanceRepo.Heartbeat(ctx, a.instanceID); err != nil {
+				a.log.Error(ctx, "Failed to update heartbeat", "error", err)
+			}
+			if err := a.feedRepo.RenewLeases(ctx, a.instanceID, leaseDuration); err != nil {
+				a.log.Error(ctx, "Failed to renew feed leases", "error", err)
+			}
+		}
+	}
+}
+
+// intervalUpdater periodically checks for updated timer intervals in the configuration and feeds,
+// and updates the ticker if needed.
+func (a *RssAggregator) intervalUpdater(ctx context.Context, current schedule) {
 	defer a.wg.Done()
 
 	t := time.NewTicker(time.Second * 2)
@@ -260,10 +893,17 @@ func (a *RssAggregator) intervalUpdater(ctx context.Context, currentInterval tim
 				a.log.Error(ctx, "Failed to read config", "error", err)
 				continue
 			}
-			if currentInterval != cfg.TimerInterval {
+			minFeedInterval, err := a.feedRepo.MinInterval(ctx)
+			if err != nil {
+				a.log.Error(ctx, "Failed to read feed intervals", "error", err)
+				continue
+			}
+
+			next := newSchedule(cfg.TimerInterval, minFeedInterval)
+			if current != next {
 				select {
-				case a.tc.intervalCh <- cfg.TimerInterval:
-					currentInterval = cfg.TimerInterval
+				case a.tc.intervalCh <- next:
+					current = next
 				default:
 				}
 			}
@@ -302,20 +942,28 @@ func (a *RssAggregator) countUpdater(ctx context.Context, currentCount int) {
 	}
 }
 
-func (a *RssAggregator) ListenShutdown(ctx context.Context) {
-	shutdownCh := make(chan os.Signal, 1)
-
-	signal.Notify(shutdownCh, syscall.SIGINT, syscall.SIGTERM)
+// hostLimitUpdater periodically checks for updated per-host limits in the configuration and applies them to the limiter.
+func (a *RssAggregator) hostLimitUpdater(ctx context.Context, maxConcurrency int, minSpacing time.Duration) {
+	defer a.wg.Done()
 
-	s := <-shutdownCh
-	msg := fmt.Sprintf("catched shutdown signal %s", s.String())
-	a.log.Notify(msg)
+	t := time.NewTicker(time.Second * 2)
+	defer t.Stop()
 
-	if err := a.Stop(); err != nil {
-		a.log.Error(ctx, "Failed to stop aggregator", "error", err)
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Debug(ctx, "host limit updater has been stopped")
+			return
+		case <-t.C:
+			cfg, err := a.configRepo.Get(ctx)
+			if err != nil {
+				a.log.Error(ctx, "Failed to read config", "error", err)
+				continue
+			}
+			if maxConcurrency != cfg.HostMaxConcurrency || minSpacing != cfg.HostMinSpacing {
+				maxConcurrency, minSpacing = cfg.HostMaxConcurrency, cfg.HostMinSpacing
+				a.wc.limiter.SetLimits(maxConcurrency, minSpacing)
+			}
+		}
 	}
-	a.cleanDb()
-
-	msg = "graceful shutdown completed!"
-	a.log.Notify(msg)
 }
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"RSSHub/internal/adapters/httpadapter"
+	"RSSHub/internal/adapters/repo"
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AddAlert validates the alert and creates it for the user with a new signing secret,
+// scope of the alert must be a subscribed feed. The secret is returned only here.
+func (a *RssAggregator) AddAlert(user string, alert *models.Alert) (*models.Alert, error) {
+	const op = "RssAggregator.AddAlert"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("match", alert.Match),
+		slog.String("pattern", alert.Pattern),
+		slog.String("feed name", alert.FeedName),
+	)
+
+	if _, err := compileAlert(alert); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := httpadapter.CheckWebhookURL(ctx, alert.WebhookURL); err != nil {
+		if errors.Is(err, httpadapter.ErrForbiddenAddress) {
+			return nil, fmt.Errorf("%w: webhook must be a public address", models.ErrInvalidAlert)
+		}
+		log.Warn("Failed to check webhook", "error", err)
+		return nil, fmt.Errorf("%w: webhook host cannot be resolved", models.ErrInvalidAlert)
+	}
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	alert.UserID = userID
+
+	var feedID string
+	if alert.FeedName != "" {
+		feed, err := a.feedRepo.Get(ctx, userID, alert.FeedName)
+		if err != nil {
+			if errors.Is(err, repo.ErrFeedNotFound) {
+				return nil, models.ErrFeedNotFound
+			}
+			log.Error("Failed to get feed", "error", err)
+			return nil, errors.New("failed to get feed")
+		}
+		feedID = feed.ID
+	}
+
+	secret, _, err := newToken()
+	if err != nil {
+		log.Error("Failed to generate alert secret", "error", err)
+		return nil, errors.New("failed to generate alert secret")
+	}
+	alert.Secret = secret
+
+	if err := a.alertRepo.Create(ctx, alert, feedID); err != nil {
+		log.Error("Failed to create alert", "error", err)
+		return nil, errors.New("failed to create alert")
+	}
+
+	return alert, nil
+}
+
+// ListAlerts returns alerts of the user in the order they were created, secrets are not returned.
+func (a *RssAggregator) ListAlerts(user string) ([]*models.Alert, error) {
+	const op = "RssAggregator.ListAlerts"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := a.alertRepo.List(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get alert list", "error", err)
+		return nil, errors.New("failed to get alert list")
+	}
+
+	if len(alerts) == 0 {
+		return nil, models.ErrAlertsNotFound
+	}
+
+	return alerts, nil
+}
+
+// DeleteAlert deletes the alert of the user by id, its pending deliveries are dropped.
+func (a *RssAggregator) DeleteAlert(user, id string) error {
+	const op = "RssAggregator.DeleteAlert"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("alert id", id),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.alertRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repo.ErrAlertNotFound) {
+			return models.ErrAlertNotFound
+		}
+		log.Error("Failed to delete alert", "error", err)
+		return errors.New("failed to delete alert")
+	}
+
+	return nil
+}
+
+// ListDeliveries shows webhook deliveries of alerts of the user, the most recent first.
+func (a *RssAggregator) ListDeliveries(user string, filter models.DeliveryFilter) ([]*models.AlertDelivery, error) {
+	const op = "RssAggregator.ListDeliveries"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("status", filter.Status),
+		slog.Int("deliveries count", filter.Num),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = userID
+
+	deliveries, err := a.alertRepo.ListDeliveries(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get deliveries list", "error", err)
+		return nil, errors.New("failed to get deliveries list")
+	}
+
+	if len(deliveries) == 0 {
+		return nil, models.ErrDeliveriesNotFound
+	}
+
+	return deliveries, nil
+}
+
+// alertMatcher is a validated alert ready to be matched against articles
+type alertMatcher struct {
+	*models.Alert
+	pattern string         // Lower-cased pattern of keyword alerts
+	re      *regexp.Regexp // Case insensitive expression of regex alerts
+}
+
+// compileAlert validates the alert, errors wrap models.ErrInvalidAlert
+func compileAlert(alert *models.Alert) (*alertMatcher, error) {
+	if alert.Pattern == "" {
+		return nil, fmt.Errorf("%w: pattern is empty", models.ErrInvalidAlert)
+	}
+
+	u, err := url.Parse(alert.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook must be an absolute http or https URL", models.ErrInvalidAlert)
+	}
+
+	m := &alertMatcher{Alert: alert}
+	switch alert.Match {
+	case models.AlertMatchKeyword:
+		m.pattern = strings.ToLower(alert.Pattern)
+	case models.AlertMatchRegex:
+		// Compiled alone first, so errors show the pattern as it was given
+		if _, err := regexp.Compile(alert.Pattern); err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrInvalidAlert, err)
+		}
+		m.re = regexp.MustCompile("(?i)" + alert.Pattern)
+	default:
+		return nil, fmt.Errorf("%w: match must be keyword or regex", models.ErrInvalidAlert)
+	}
+
+	return m, nil
+}
+
+// matches reports whether the title or the description of the article matches the alert
+func (m *alertMatcher) matches(article *models.RSSItem) bool {
+	return m.matchString(article.Title) || m.matchString(article.Description)
+}
+
+func (m *alertMatcher) matchString(s string) bool {
+	if m.re != nil {
+		return m.re.MatchString(s)
+	}
+	return strings.Contains(strings.ToLower(s), m.pattern)
+}
+
+// deliveryBackoff returns the delay before the next attempt of a delivery after the given failed attempt
+func deliveryBackoff(attempt int) time.Duration {
+	delay := deliveryBaseBackoff
+	for i := 1; i < attempt && delay < maxDeliveryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDeliveryBackoff)
+}
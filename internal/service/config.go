@@ -1,6 +1,7 @@
 package service
 
 import (
+	"RSSHub/internal/adapters/repo"
 	"RSSHub/internal/domain/models"
 	"RSSHub/pkg/utils"
 	"context"
@@ -29,6 +30,51 @@ func (a *RssAggregator) SetInterval(changed time.Duration) error {
 	return nil
 }
 
+// SetFeedInterval sets own fetch interval of the feed of the user, zero resets it to the global interval.
+// Feeds are fetched once for all subscribers, so the interval applies to every subscriber and only admins may set it.
+func (a *RssAggregator) SetFeedInterval(user, feedName string, changed time.Duration) error {
+	const op = "RssAggregator.SetFeedInterval"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("feed name", feedName),
+		slog.Duration("new duration", changed),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.adminID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	feed, err := a.feedRepo.Get(ctx, userID, feedName)
+	if err != nil {
+		if errors.Is(err, repo.ErrFeedNotFound) {
+			return models.ErrFeedNotFound
+		}
+		log.Error("Failed to get feed", "error", err)
+		return errors.New("failed to get feed")
+	}
+
+	var interval *time.Duration
+	if changed > 0 {
+		interval = &changed
+	}
+
+	if err := a.feedRepo.UpdateInterval(ctx, feed.ID, interval); err != nil {
+		log.Error("Failed to update feed interval", "error", err)
+		return errors.New("failed to update feed interval")
+	}
+
+	msg := fmt.Sprintf("Interval of fetching feed %s reset to the global one", feedName)
+	if interval != nil {
+		msg = fmt.Sprintf("Interval of fetching feed %s changed to %s", feedName, utils.PrettyDuration(changed))
+	}
+	a.log.Notify(msg)
+	return nil
+}
+
 func (a *RssAggregator) Resize(workers int) error {
 	const op = "RssAggregator.Resize"
 	log := a.log.GetSlogLogger().With(
@@ -52,6 +98,63 @@ func (a *RssAggregator) Resize(workers int) error {
 	return nil
 }
 
+// SetHostLimits changes the maximum of concurrent requests and the minimal spacing between requests to the same host.
+func (a *RssAggregator) SetHostLimits(maxConcurrency int, minSpacing time.Duration) error {
+	const op = "RssAggregator.SetHostLimits"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.Int("max concurrency", maxConcurrency),
+		slog.Duration("min spacing", minSpacing),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := a.configRepo.UpdateHostLimits(ctx, maxConcurrency, minSpacing); err != nil {
+		log.Error("Failed to update host limits", "error", err)
+		return errors.New("failed to update host limits")
+	}
+
+	msg := fmt.Sprintf("Per-host limits changed to %d concurrent requests, %s between requests", maxConcurrency, minSpacing)
+	a.log.Notify(msg)
+	return nil
+}
+
+// UpdateConfig validates the config and replaces the stored one by a single update, so it is applied entirely or not at all.
+func (a *RssAggregator) UpdateConfig(cfg *models.RssConfig) error {
+	const op = "RssAggregator.UpdateConfig"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.Int("worker count", cfg.WorkerCount),
+		slog.Duration("interval", cfg.TimerInterval),
+		slog.Int("max concurrency", cfg.HostMaxConcurrency),
+		slog.Duration("min spacing", cfg.HostMinSpacing),
+	)
+
+	switch {
+	case cfg.WorkerCount < 1 || cfg.WorkerCount > 10000:
+		return fmt.Errorf("%w: worker count must be between 1 and 10000", models.ErrInvalidConfig)
+	case cfg.TimerInterval <= 0:
+		return fmt.Errorf("%w: interval must be positive", models.ErrInvalidConfig)
+	case cfg.HostMaxConcurrency < 1:
+		return fmt.Errorf("%w: host max concurrency must be greater than 0", models.ErrInvalidConfig)
+	case cfg.HostMinSpacing < 0:
+		return fmt.Errorf("%w: host min spacing must not be negative", models.ErrInvalidConfig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := a.configRepo.Update(ctx, cfg); err != nil {
+		log.Error("Failed to update config", "error", err)
+		return errors.New("failed to update config")
+	}
+
+	msg := fmt.Sprintf("Config changed: interval = %s, workers = %d, per-host limits = %d concurrent requests, %s between requests",
+		utils.PrettyDuration(cfg.TimerInterval), cfg.WorkerCount, cfg.HostMaxConcurrency, cfg.HostMinSpacing)
+	a.log.Notify(msg)
+	return nil
+}
+
 // LoadConfig retrieves the RSS aggregator configuration, checks running state, and updates run status in the repository.
 func (a *RssAggregator) GetConfig(ctx context.Context) (*models.RssConfig, error) {
 	const op = "RssAggregator.loadConfig"
@@ -69,3 +172,39 @@ func (a *RssAggregator) GetConfig(ctx context.Context) (*models.RssConfig, error
 
 	return cfg, nil
 }
+
+// GetStatus returns the configuration with the current state of feeds scheduling.
+func (a *RssAggregator) GetStatus(ctx context.Context) (*models.AggregatorStatus, error) {
+	const op = "RssAggregator.GetStatus"
+	log := a.log.GetSlogLogger().With("op", op)
+
+	cfg, err := a.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heldBack, err := a.feedRepo.CountHeldBack(ctx, cfg.TimerInterval)
+	if err != nil {
+		log.Error("Failed to count held back feeds", "error", err)
+		return nil, errors.New("failed to count held back feeds")
+	}
+
+	jobs, err := a.jobRepo.CountByStatus(ctx)
+	if err != nil {
+		log.Error("Failed to count fetch jobs", "error", err)
+		return nil, errors.New("failed to count fetch jobs")
+	}
+
+	instances, err := a.instanceRepo.ListAlive(ctx, instanceStaleAfter)
+	if err != nil {
+		log.Error("Failed to list running instances", "error", err)
+		return nil, errors.New("failed to list running instances")
+	}
+
+	return &models.AggregatorStatus{
+		Config:        cfg,
+		HeldBackFeeds: heldBack,
+		Jobs:          jobs,
+		Instances:     instances,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"RSSHub/internal/adapters/repo"
 	"RSSHub/internal/domain/models"
 	"context"
 	"errors"
@@ -9,72 +10,246 @@ import (
 	"time"
 )
 
-// Shows <num> recent articles for the given feed.
-func (a *RssAggregator) GetArticles(feedName string, num int) ([]*models.RSSItem, error) {
+// Shows <num> recent articles of the user for the given feed or group, of all subscribed feeds when neither is given.
+func (a *RssAggregator) GetArticles(user string, filter models.ArticleFilter) ([]*models.RSSItem, error) {
 	const op = "RssAggregator.GetArticles"
 	log := a.log.GetSlogLogger().With(
 		slog.String("op:%s", op),
-		slog.String("feed name", feedName),
-		slog.Int("article count", num),
+		slog.String("user", user),
+		slog.String("feed name", filter.FeedName),
+		slog.String("group", filter.Group),
+		slog.Int("article count", filter.Num),
+		slog.Bool("with media", filter.WithMedia),
+		slog.String("category", filter.Category),
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	var (
-		articles []*models.RSSItem
-		err      error
-	)
-	articles, err = a.articleRepo.List(ctx, feedName, num)
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = userID
+
+	switch {
+	case filter.FeedName != "":
+		if _, err := a.feedRepo.Get(ctx, userID, filter.FeedName); err != nil {
+			if errors.Is(err, repo.ErrFeedNotFound) {
+				return nil, models.ErrFeedNotFound
+			}
+			log.Error("Failed to get feed", "error", err)
+			return nil, errors.New("failed to get feed")
+		}
+	case filter.Group != "":
+		if _, err := a.group(ctx, userID, filter.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	articles, err := a.articleRepo.List(ctx, filter)
 	if err != nil {
 		log.Error("Failed to get articles list", "error", err)
 		return nil, errors.New("failed to get articles list")
 	}
 
 	if len(articles) == 0 {
-		return nil, fmt.Errorf("articles by feed %s are not found", feedName)
+		switch {
+		case filter.FeedName != "":
+			return nil, fmt.Errorf("%w by feed %s", models.ErrArticlesNotFound, filter.FeedName)
+		case filter.Group != "":
+			return nil, fmt.Errorf("%w by group %s", models.ErrArticlesNotFound, filter.Group)
+		}
+		return nil, models.ErrArticlesNotFound
+	}
+
+	if filter.WithMedia {
+		if err := a.articleRepo.LoadEnclosures(ctx, articles); err != nil {
+			log.Error("Failed to load article enclosures", "error", err)
+			return nil, errors.New("failed to load article enclosures")
+		}
 	}
 
 	return articles, nil
 }
 
-// Shows the <num> most recently added feeds.
-func (a *RssAggregator) ListFeeds(num int) ([]*models.Feed, error) {
-	const op = "RssAggregator.ListFeeds"
+// SearchArticles finds articles of feeds the user is subscribed to matching the full-text query, the most relevant first.
+func (a *RssAggregator) SearchArticles(user string, filter models.SearchFilter) ([]*models.SearchResult, error) {
+	const op = "RssAggregator.SearchArticles"
 	log := a.log.GetSlogLogger().With(
 		slog.String("op", op),
-		slog.Int("feeds count", num),
+		slog.String("user", user),
+		slog.String("query", filter.Query),
+		slog.String("feed name", filter.FeedName),
+		slog.Time("since", filter.Since),
+		slog.Int("article count", filter.Num),
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	var (
-		feeds []*models.Feed
-		err   error
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = userID
+
+	results, err := a.articleRepo.Search(ctx, filter)
+	if err != nil {
+		log.Error("Failed to search articles", "error", err)
+		return nil, errors.New("failed to search articles")
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%w by query %q", models.ErrArticlesNotFound, filter.Query)
+	}
+
+	return results, nil
+}
+
+// MarkRead marks articles of the feed published before the time as read for the user, the number of newly read articles is returned.
+func (a *RssAggregator) MarkRead(user, feedName string, before time.Time) (int64, error) {
+	const op = "RssAggregator.MarkRead"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("feed name", feedName),
+		slog.Time("before", before),
 	)
-	switch num {
-	case 0:
-		feeds, err = a.feedRepo.ListAll(ctx)
-	default:
-		feeds, err = a.feedRepo.List(ctx, num)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return 0, err
 	}
+
+	subscribed, err := a.feedRepo.Subscribed(ctx, userID, feedName)
+	if err != nil {
+		log.Error("Failed to check subscription", "error", err)
+		return 0, errors.New("failed to check subscription")
+	}
+
+	if !subscribed {
+		return 0, models.ErrFeedNotFound
+	}
+
+	count, err := a.articleRepo.MarkRead(ctx, userID, feedName, before)
+	if err != nil {
+		log.Error("Failed to mark articles as read", "error", err)
+		return 0, errors.New("failed to mark articles as read")
+	}
+
+	return count, nil
+}
+
+// StarArticle stars or unstars the article by id for the user.
+func (a *RssAggregator) StarArticle(user, id string, starred bool) error {
+	const op = "RssAggregator.StarArticle"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("article id", id),
+		slog.Bool("starred", starred),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.articleRepo.SetStarred(ctx, userID, id, starred); err != nil {
+		if errors.Is(err, repo.ErrArticleNotFound) {
+			return models.ErrArticleNotFound
+		}
+		log.Error("Failed to star article", "error", err)
+		return errors.New("failed to star article")
+	}
+
+	return nil
+}
+
+// Shows the <num> feeds the user subscribed to most recently, only those of the group when it is given.
+func (a *RssAggregator) ListFeeds(user string, filter models.FeedFilter) ([]*models.Feed, error) {
+	const op = "RssAggregator.ListFeeds"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", filter.Group),
+		slog.Int("feeds count", filter.Num),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Group != "" {
+		if _, err := a.group(ctx, userID, filter.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	feeds, err := a.feedRepo.List(ctx, userID, filter)
 	if err != nil {
 		log.Error("Failed to get feed list", "error", err)
 		return nil, errors.New("failed to get feed list")
 	}
 
 	if len(feeds) == 0 {
-		return nil, errors.New("feeds are not found")
+		if filter.Group != "" {
+			return nil, fmt.Errorf("%w in group %s", models.ErrFeedsNotFound, filter.Group)
+		}
+		return nil, models.ErrFeedsNotFound
 	}
 
 	return feeds, nil
 }
 
-func (a *RssAggregator) AddFeed(name, desc, url string) error {
+// GetFeed returns the feed the user is subscribed to by name.
+func (a *RssAggregator) GetFeed(user, name string) (*models.Feed, error) {
+	const op = "RssAggregator.GetFeed"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("feed name", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := a.feedRepo.Get(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrFeedNotFound) {
+			return nil, models.ErrFeedNotFound
+		}
+		log.Error("Failed to get feed", "error", err)
+		return nil, errors.New("failed to get feed")
+	}
+
+	return feed, nil
+}
+
+// AddFeed subscribes the user to a new feed. Feeds are fetched once for all subscribers,
+// so when a feed with the URL already exists the user is subscribed to it under its own name.
+// The subscribed feed is returned.
+func (a *RssAggregator) AddFeed(user, name, desc, url string) (*models.Feed, error) {
 	const op = "RssAggregator.AddFeed"
 	log := a.log.GetSlogLogger().With(
 		slog.String("op", op),
+		slog.String("user", user),
 		slog.String("feed name", name),
 		slog.String("URL", url),
 	)
@@ -82,55 +257,114 @@ func (a *RssAggregator) AddFeed(name, desc, url string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	// Feed existense check
-	exist, err := a.feedRepo.Exist(ctx, name)
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feed names are unique among feeds of the user only
+	exist, err := a.feedRepo.Subscribed(ctx, userID, name)
 	if err != nil {
 		log.Error("Failed to check feed existence", "error", err)
-		return errors.New("failed to check feed existence")
+		return nil, errors.New("failed to check feed existence")
 	}
 
 	if exist {
-		return fmt.Errorf("feed name must be unique")
+		return nil, models.ErrFeedExists
+	}
+
+	// Feed of another user with the same URL
+	feed, err := a.feedRepo.GetByURL(ctx, url)
+	switch {
+	case err == nil:
+		subscribed, err := a.feedRepo.Subscribe(ctx, userID, feed.ID, name, desc)
+		if err != nil {
+			log.Error("Failed to subscribe to feed", "error", err)
+			return nil, errors.New("failed to subscribe to feed")
+		}
+		if !subscribed {
+			return nil, fmt.Errorf("%w %s", models.ErrSubscribed, url)
+		}
+		feed.Name = name
+		feed.Description = desc
+		return feed, nil
+	case !errors.Is(err, repo.ErrFeedNotFound):
+		log.Error("Failed to find feed by URL", "error", err)
+		return nil, errors.New("failed to find feed by URL")
 	}
 
 	// Creating a new feed
-	feed := &models.Feed{
+	feed = &models.Feed{
 		Name:        name,
 		Description: desc,
 		URL:         url,
 	}
-	if err := a.feedRepo.Create(ctx, feed); err != nil {
+	if err := a.feedRepo.Create(ctx, userID, feed); err != nil {
 		log.Error("Failed to create new feed", "error", err)
-		return errors.New("failed to create new feed")
+		return nil, errors.New("failed to create new feed")
 	}
-	return nil
+	return feed, nil
 }
 
-func (a *RssAggregator) DeleteFeed(name string) error {
+// DeleteFeed unsubscribes the user from the feed, the feed is deleted when it has no subscribers left.
+func (a *RssAggregator) DeleteFeed(user, name string) error {
 	const op = "RssAggregator.DeleteFeed"
 	log := a.log.GetSlogLogger().With(
 		slog.String("op: %s", op),
+		slog.String("user", user),
 		slog.String("feed name", name),
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	// Feed existense check
-	exist, err := a.feedRepo.Exist(ctx, name)
+	userID, err := a.userID(ctx, user)
 	if err != nil {
-		log.Error("Failed to check feed existence", "error", err)
-		return errors.New("failed to check feed existence")
-	}
-
-	if !exist {
-		return errors.New("the feed is not exist")
+		return err
 	}
 
-	if err := a.feedRepo.Delete(ctx, name); err != nil {
+	if err := a.feedRepo.Delete(ctx, userID, name); err != nil {
+		if errors.Is(err, repo.ErrFeedNotFound) {
+			return models.ErrFeedNotFound
+		}
 		log.Error("Failed to delete feed", "error", err)
 		return errors.New("failed to delete feed")
 	}
 
 	return nil
 }
+
+// EnableFeed clears the failure state of the feed of the user, so a disabled feed is fetched again.
+// Feeds are shared by their subscribers, so only admins may enable them.
+func (a *RssAggregator) EnableFeed(user, name string) error {
+	const op = "RssAggregator.EnableFeed"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("feed name", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.adminID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	feed, err := a.feedRepo.Get(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrFeedNotFound) {
+			return models.ErrFeedNotFound
+		}
+		log.Error("Failed to get feed", "error", err)
+		return errors.New("failed to get feed")
+	}
+
+	if err := a.feedRepo.ResetFailures(ctx, feed.ID); err != nil {
+		log.Error("Failed to enable feed", "error", err)
+		return errors.New("failed to enable feed")
+	}
+
+	return nil
+}
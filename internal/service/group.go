@@ -0,0 +1,203 @@
+package service
+
+import (
+	"RSSHub/internal/adapters/repo"
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// GetGroup returns the group of the user by name.
+func (a *RssAggregator) GetGroup(user, name string) (*models.Group, error) {
+	const op = "RssAggregator.GetGroup"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := a.groupRepo.Get(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrGroupNotFound) {
+			return nil, models.ErrGroupNotFound
+		}
+		log.Error("Failed to get group", "error", err)
+		return nil, errors.New("failed to get group")
+	}
+
+	return group, nil
+}
+
+// CreateGroup creates a new empty group of the user.
+func (a *RssAggregator) CreateGroup(user, name string) error {
+	const op = "RssAggregator.CreateGroup"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.groupRepo.Create(ctx, userID, name); err != nil {
+		if errors.Is(err, repo.ErrGroupExists) {
+			return models.ErrGroupExists
+		}
+		log.Error("Failed to create group", "error", err)
+		return errors.New("failed to create group")
+	}
+
+	return nil
+}
+
+// AddGroupFeed adds the feed to the group of the user, the user must be subscribed to the feed.
+func (a *RssAggregator) AddGroupFeed(user, group, feedName string) error {
+	const op = "RssAggregator.AddGroupFeed"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", group),
+		slog.String("feed name", feedName),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.groupRepo.AddFeed(ctx, userID, group, feedName); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrGroupNotFound):
+			return models.ErrGroupNotFound
+		case errors.Is(err, repo.ErrFeedNotFound):
+			return models.ErrFeedNotFound
+		}
+		log.Error("Failed to add feed to group", "error", err)
+		return errors.New("failed to add feed to group")
+	}
+
+	return nil
+}
+
+// RemoveGroupFeed removes the feed from the group of the user, the user stays subscribed to the feed.
+func (a *RssAggregator) RemoveGroupFeed(user, group, feedName string) error {
+	const op = "RssAggregator.RemoveGroupFeed"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", group),
+		slog.String("feed name", feedName),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.groupRepo.RemoveFeed(ctx, userID, group, feedName); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrGroupNotFound):
+			return models.ErrGroupNotFound
+		case errors.Is(err, repo.ErrFeedNotFound):
+			return fmt.Errorf("%w in group %s", models.ErrFeedNotFound, group)
+		}
+		log.Error("Failed to remove feed from group", "error", err)
+		return errors.New("failed to remove feed from group")
+	}
+
+	return nil
+}
+
+// DeleteGroup deletes the group of the user, its feeds stay subscribed.
+func (a *RssAggregator) DeleteGroup(user, name string) error {
+	const op = "RssAggregator.DeleteGroup"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("group", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.groupRepo.Delete(ctx, userID, name); err != nil {
+		if errors.Is(err, repo.ErrGroupNotFound) {
+			return models.ErrGroupNotFound
+		}
+		log.Error("Failed to delete group", "error", err)
+		return errors.New("failed to delete group")
+	}
+
+	return nil
+}
+
+// ListGroups returns groups of the user with names of their feeds.
+func (a *RssAggregator) ListGroups(user string) ([]*models.Group, error) {
+	const op = "RssAggregator.ListGroups"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := a.groupRepo.List(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get group list", "error", err)
+		return nil, errors.New("failed to get group list")
+	}
+
+	if len(groups) == 0 {
+		return nil, models.ErrGroupsNotFound
+	}
+
+	return groups, nil
+}
+
+// group returns the group of the user by name.
+func (a *RssAggregator) group(ctx context.Context, userID, name string) (*models.Group, error) {
+	group, err := a.groupRepo.Get(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrGroupNotFound) {
+			return nil, models.ErrGroupNotFound
+		}
+		a.log.GetSlogLogger().Error("Failed to get group", "group", name, "error", err)
+		return nil, errors.New("failed to get group")
+	}
+
+	return group, nil
+}
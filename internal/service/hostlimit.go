@@ -0,0 +1,107 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// HostLimiter caps the number of concurrent requests to the same host
+	// and keeps a minimal spacing between their starts.
+	HostLimiter struct {
+		maxConcurrent int           // Maximum of requests in flight per host.
+		spacing       time.Duration // Minimal time between starts of requests to the same host.
+
+		hosts map[string]*hostState // State of every host seen so far.
+		mu    sync.Mutex            // Mutex protecting limits and hosts state.
+	}
+
+	// hostState tracks requests to a single host.
+	hostState struct {
+		active int       // Requests in flight.
+		next   time.Time // Earliest start of the next request.
+	}
+)
+
+func NewHostLimiter(maxConcurrent int, spacing time.Duration) *HostLimiter {
+	return &HostLimiter{
+		maxConcurrent: max(maxConcurrent, 1),
+		spacing:       spacing,
+		hosts:         make(map[string]*hostState),
+	}
+}
+
+// SetLimits changes the limits, requests already in flight are not affected.
+func (l *HostLimiter) SetLimits(maxConcurrent int, spacing time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxConcurrent = max(maxConcurrent, 1)
+	l.spacing = spacing
+}
+
+// TryAcquire allows a request to the host when the limits allow it right now, it never waits.
+// The returned function must be called when the allowed request is finished. When the request is not allowed,
+// ok is false and retryAt is the earliest time it may be allowed, it is now when the host has no free slot.
+func (l *HostLimiter) TryAcquire(host string) (release func(), retryAt time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.state(host)
+	now := time.Now()
+	if now.Before(st.next) {
+		return nil, st.next, false
+	}
+	if st.active >= l.maxConcurrent {
+		return nil, now, false
+	}
+
+	st.active++
+	st.next = now.Add(l.spacing)
+	return func() { l.release(host) }, time.Time{}, true
+}
+
+// Defer postpones all new requests to the host until the given time, e.g. on Retry-After.
+func (l *HostLimiter) Defer(host string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.state(host)
+	if until.After(st.next) {
+		st.next = until
+	}
+}
+
+func (l *HostLimiter) release(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.state(host)
+	st.active--
+
+	// Forgetting idle hosts, so the map does not grow with deleted feeds
+	if st.active == 0 && time.Now().After(st.next) {
+		delete(l.hosts, host)
+	}
+}
+
+// state returns the state of the host, l.mu must be held.
+func (l *HostLimiter) state(host string) *hostState {
+	st, ok := l.hosts[host]
+	if !ok {
+		st = &hostState{}
+		l.hosts[host] = st
+	}
+	return st
+}
+
+// hostOf returns the lower-cased host of the feed URL, the URL itself when it cannot be parsed.
+func hostOf(feedURL string) string {
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Host == "" {
+		return feedURL
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ListJobs shows fetch jobs of the queue, the most recently changed first.
+func (a *RssAggregator) ListJobs(filter models.JobFilter) ([]*models.FetchJob, error) {
+	const op = "RssAggregator.ListJobs"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("status", filter.Status),
+		slog.Int("jobs count", filter.Num),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	jobs, err := a.jobRepo.List(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get jobs list", "error", err)
+		return nil, errors.New("failed to get jobs list")
+	}
+
+	if len(jobs) == 0 {
+		return nil, models.ErrJobsNotFound
+	}
+
+	return jobs, nil
+}
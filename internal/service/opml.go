@@ -0,0 +1,212 @@
+package service
+
+import (
+	"RSSHub/internal/domain/models"
+	"RSSHub/pkg/opml"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// groupSeparator joins names of nested OPML folders into a group name, e.g. "dev/golang"
+const groupSeparator = "/"
+
+// ImportOPML subscribes the user to feeds of the OPML document with AddFeed. Feeds the user is already subscribed to
+// by name or URL are skipped, folders of the subscriptions become groups of the user, nested folders are joined with "/".
+// The outcome of every subscription is returned.
+func (a *RssAggregator) ImportOPML(user string, r io.Reader) ([]*models.ImportResult, error) {
+	const op = "RssAggregator.ImportOPML"
+	log := a.log.GetSlogLogger().With(slog.String("op", op), slog.String("user", user))
+
+	doc, err := opml.Parse(r)
+	if err != nil {
+		log.Error("Failed to parse OPML document", "error", err)
+		return nil, fmt.Errorf("failed to parse OPML document: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds, err := a.feedRepo.List(ctx, userID, models.FeedFilter{})
+	if err != nil {
+		log.Error("Failed to get feed list", "error", err)
+		return nil, errors.New("failed to get feed list")
+	}
+
+	// Names of subscribed feeds by URL, both names and URLs identify duplicates
+	byURL := make(map[string]string, len(feeds))
+	names := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		byURL[feed.URL] = feed.Name
+		names[feed.Name] = true
+	}
+
+	var results []*models.ImportResult
+	for _, sub := range doc.Subscriptions() {
+		url := strings.TrimSpace(sub.XMLURL)
+		name := sub.Name()
+		if name == "" {
+			name = url
+		}
+		desc := strings.TrimSpace(sub.Description)
+		if desc == "" {
+			desc = name
+		}
+
+		result := &models.ImportResult{
+			Name:  name,
+			URL:   url,
+			Group: strings.Join(sub.Folders, groupSeparator),
+		}
+		results = append(results, result)
+
+		switch existing, ok := byURL[url]; {
+		case ok:
+			// The existing feed still joins the group of the subscription
+			result.Name = existing
+			result.Status = models.ImportSkipped
+			result.Reason = "feed with the same URL already exists"
+		case names[name]:
+			result.Status = models.ImportSkipped
+			result.Reason = "feed with the same name already exists"
+			result.Group = ""
+		default:
+			// A feed of another user with the URL is subscribed to under its own name
+			feed, err := a.AddFeed(user, name, desc, url)
+			if err != nil {
+				result.Status = models.ImportFailed
+				result.Reason = err.Error()
+				result.Group = ""
+				continue
+			}
+			byURL[url] = feed.Name
+			names[feed.Name] = true
+			result.Name = feed.Name
+			result.Status = models.ImportAdded
+		}
+
+		if result.Group == "" {
+			continue
+		}
+		if err := a.CreateGroup(user, result.Group); err != nil && !errors.Is(err, models.ErrGroupExists) {
+			result.Reason = joinReason(result.Reason, err.Error())
+			continue
+		}
+		if err := a.AddGroupFeed(user, result.Group, result.Name); err != nil {
+			result.Reason = joinReason(result.Reason, err.Error())
+		}
+	}
+
+	return results, nil
+}
+
+// ExportOPML writes feeds the user is subscribed to as an OPML 2.0 document. Feeds of groups are nested in folders of the groups,
+// so a feed is listed once per group, feeds without a group are listed at the top level.
+func (a *RssAggregator) ExportOPML(user string, w io.Writer) error {
+	const op = "RssAggregator.ExportOPML"
+	log := a.log.GetSlogLogger().With(slog.String("op", op), slog.String("user", user))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	feeds, err := a.feedRepo.List(ctx, userID, models.FeedFilter{})
+	if err != nil {
+		log.Error("Failed to get feed list", "error", err)
+		return errors.New("failed to get feed list")
+	}
+
+	groups, err := a.groupRepo.List(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get group list", "error", err)
+		return errors.New("failed to get group list")
+	}
+
+	// Oldest subscriptions first
+	slices.Reverse(feeds)
+
+	byName := make(map[string]*models.Feed, len(feeds))
+	for _, feed := range feeds {
+		byName[feed.Name] = feed
+	}
+
+	doc := &opml.Document{
+		Version: "2.0",
+		Head: opml.Head{
+			Title:       "RSSHub subscriptions",
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	grouped := make(map[string]bool)
+	for _, group := range groups {
+		folder := folderOutline(&doc.Body.Outlines, strings.Split(group.Name, groupSeparator))
+		for _, name := range group.FeedNames {
+			if feed, ok := byName[name]; ok {
+				folder.Outlines = append(folder.Outlines, feedOutline(feed))
+				grouped[name] = true
+			}
+		}
+	}
+
+	for _, feed := range feeds {
+		if !grouped[feed.Name] {
+			doc.Body.Outlines = append(doc.Body.Outlines, feedOutline(feed))
+		}
+	}
+
+	if err := doc.Write(w); err != nil {
+		log.Error("Failed to write OPML document", "error", err)
+		return errors.New("failed to write OPML document")
+	}
+
+	return nil
+}
+
+// folderOutline returns the folder at the path, missing folders are created
+func folderOutline(outlines *[]*opml.Outline, path []string) *opml.Outline {
+	var folder *opml.Outline
+	for _, name := range path {
+		i := slices.IndexFunc(*outlines, func(o *opml.Outline) bool {
+			return o.XMLURL == "" && o.Text == name
+		})
+		if i < 0 {
+			*outlines = append(*outlines, &opml.Outline{Text: name, Title: name})
+			i = len(*outlines) - 1
+		}
+		folder = (*outlines)[i]
+		outlines = &folder.Outlines
+	}
+	return folder
+}
+
+func feedOutline(feed *models.Feed) *opml.Outline {
+	return &opml.Outline{
+		Text:        feed.Name,
+		Title:       feed.Name,
+		Type:        "rss",
+		XMLURL:      feed.URL,
+		Description: feed.Description,
+	}
+}
+
+func joinReason(reason, err string) string {
+	if reason == "" {
+		return err
+	}
+	return reason + "; " + err
+}
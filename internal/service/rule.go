@@ -0,0 +1,318 @@
+package service
+
+import (
+	"RSSHub/internal/adapters/repo"
+	"RSSHub/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ruleTestSize is the number of the most recent stored articles a rule is tested against
+const ruleTestSize = 1000
+
+// AddRule validates the rule and creates it for the user, scope of the rule must be a subscribed feed or a group of the user.
+func (a *RssAggregator) AddRule(user string, rule *models.Rule) (*models.Rule, error) {
+	const op = "RssAggregator.AddRule"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("field", rule.Field),
+		slog.String("match", rule.Match),
+		slog.String("pattern", rule.Pattern),
+		slog.String("action", rule.Action),
+	)
+
+	if _, err := compileRule(rule); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	rule.UserID = userID
+
+	var feedID, groupID string
+	switch {
+	case rule.FeedName != "":
+		feed, err := a.feedRepo.Get(ctx, userID, rule.FeedName)
+		if err != nil {
+			if errors.Is(err, repo.ErrFeedNotFound) {
+				return nil, models.ErrFeedNotFound
+			}
+			log.Error("Failed to get feed", "error", err)
+			return nil, errors.New("failed to get feed")
+		}
+		feedID = feed.ID
+	case rule.Group != "":
+		group, err := a.group(ctx, userID, rule.Group)
+		if err != nil {
+			return nil, err
+		}
+		groupID = group.ID
+	}
+
+	if err := a.ruleRepo.Create(ctx, rule, feedID, groupID); err != nil {
+		log.Error("Failed to create rule", "error", err)
+		return nil, errors.New("failed to create rule")
+	}
+
+	return rule, nil
+}
+
+// ListRules returns rules of the user in the order they were created.
+func (a *RssAggregator) ListRules(user string) ([]*models.Rule, error) {
+	const op = "RssAggregator.ListRules"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := a.ruleRepo.List(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get rule list", "error", err)
+		return nil, errors.New("failed to get rule list")
+	}
+
+	if len(rules) == 0 {
+		return nil, models.ErrRulesNotFound
+	}
+
+	return rules, nil
+}
+
+// DeleteRule deletes the rule of the user by id, articles it has changed stay as they are.
+func (a *RssAggregator) DeleteRule(user, id string) error {
+	const op = "RssAggregator.DeleteRule"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("rule id", id),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := a.ruleRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repo.ErrRuleNotFound) {
+			return models.ErrRuleNotFound
+		}
+		log.Error("Failed to delete rule", "error", err)
+		return errors.New("failed to delete rule")
+	}
+
+	return nil
+}
+
+// TestRule dry-runs the rule of the user against the most recent stored articles of its scope,
+// the articles it matches are returned and nothing is changed.
+func (a *RssAggregator) TestRule(user, id string) (*models.Rule, []*models.RSSItem, error) {
+	const op = "RssAggregator.TestRule"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", user),
+		slog.String("rule id", id),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userID, err := a.userID(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rule, err := a.ruleRepo.Get(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrRuleNotFound) {
+			return nil, nil, models.ErrRuleNotFound
+		}
+		log.Error("Failed to get rule", "error", err)
+		return nil, nil, errors.New("failed to get rule")
+	}
+
+	matcher, err := compileRule(rule)
+	if err != nil {
+		log.Error("Stored rule is invalid", "error", err)
+		return nil, nil, err
+	}
+
+	articles, err := a.articleRepo.List(ctx, models.ArticleFilter{
+		UserID:   userID,
+		FeedName: rule.FeedName,
+		Group:    rule.Group,
+		Num:      ruleTestSize,
+	})
+	if err != nil {
+		log.Error("Failed to get articles list", "error", err)
+		return nil, nil, errors.New("failed to get articles list")
+	}
+
+	var matched []*models.RSSItem
+	for _, article := range articles {
+		if matcher.matches(article) {
+			matched = append(matched, article)
+		}
+	}
+
+	if len(matched) == 0 {
+		return rule, nil, models.ErrArticlesNotFound
+	}
+
+	return rule, matched, nil
+}
+
+// ruleMatcher is a validated rule ready to be matched against articles
+type ruleMatcher struct {
+	*models.Rule
+	pattern string         // Lower-cased pattern of contains rules
+	re      *regexp.Regexp // Case insensitive expression of regex rules
+}
+
+// compileRule validates the rule, errors wrap models.ErrInvalidRule
+func compileRule(rule *models.Rule) (*ruleMatcher, error) {
+	switch rule.Field {
+	case models.RuleFieldTitle, models.RuleFieldDescription, models.RuleFieldAuthor, models.RuleFieldCategory:
+	default:
+		return nil, fmt.Errorf("%w: field must be one of title, description, author, category", models.ErrInvalidRule)
+	}
+
+	switch rule.Action {
+	case models.RuleActionDrop, models.RuleActionStar, models.RuleActionMarkRead:
+		if rule.Tag != "" {
+			return nil, fmt.Errorf("%w: tag is only set by tag rules", models.ErrInvalidRule)
+		}
+	case models.RuleActionTag:
+		rule.Tag = strings.ToLower(strings.TrimSpace(rule.Tag))
+		if rule.Tag == "" {
+			return nil, fmt.Errorf("%w: tag rules need a tag", models.ErrInvalidRule)
+		}
+	default:
+		return nil, fmt.Errorf("%w: action must be one of drop, tag, star, mark-read", models.ErrInvalidRule)
+	}
+
+	if rule.FeedName != "" && rule.Group != "" {
+		return nil, fmt.Errorf("%w: rule is scoped to a feed or a group, not both", models.ErrInvalidRule)
+	}
+
+	if rule.Pattern == "" {
+		return nil, fmt.Errorf("%w: pattern is empty", models.ErrInvalidRule)
+	}
+
+	m := &ruleMatcher{Rule: rule}
+	switch rule.Match {
+	case models.RuleMatchContains:
+		m.pattern = strings.ToLower(rule.Pattern)
+	case models.RuleMatchRegex:
+		// Compiled alone first, so errors show the pattern as it was given
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrInvalidRule, err)
+		}
+		m.re = regexp.MustCompile("(?i)" + rule.Pattern)
+	default:
+		return nil, fmt.Errorf("%w: match must be contains or regex", models.ErrInvalidRule)
+	}
+
+	return m, nil
+}
+
+// matches reports whether the field of the article matches the rule, any category may match
+func (m *ruleMatcher) matches(article *models.RSSItem) bool {
+	switch m.Field {
+	case models.RuleFieldTitle:
+		return m.matchString(article.Title)
+	case models.RuleFieldDescription:
+		return m.matchString(article.Description)
+	case models.RuleFieldAuthor:
+		return m.matchString(article.Author)
+	case models.RuleFieldCategory:
+		return slices.ContainsFunc(article.Categories, m.matchString)
+	}
+	return false
+}
+
+func (m *ruleMatcher) matchString(s string) bool {
+	if m.re != nil {
+		return m.re.MatchString(s)
+	}
+	return strings.Contains(strings.ToLower(s), m.pattern)
+}
+
+// ruleOutcome is the result of rules of all subscribers on the fetched articles of a feed
+type ruleOutcome struct {
+	kept    []models.RSSItem        // Articles to store
+	states  [][]models.ArticleState // States of the kept articles by index, article ids are set once they are stored
+	dropped int                     // Articles dropped by all subscribers
+}
+
+// applyRules evaluates the rules on the articles. Articles dropped by rules of every subscriber are not kept,
+// those dropped by some of them are hidden from those only.
+func applyRules(matchers []*ruleMatcher, subscribers int, articles []models.RSSItem) ruleOutcome {
+	var outcome ruleOutcome
+	for _, article := range articles {
+		states := make(map[string]*models.ArticleState)
+		state := func(userID string) *models.ArticleState {
+			if states[userID] == nil {
+				states[userID] = &models.ArticleState{UserID: userID}
+			}
+			return states[userID]
+		}
+
+		droppedBy := make(map[string]bool)
+		for _, m := range matchers {
+			if !m.matches(&article) {
+				continue
+			}
+
+			switch m.Action {
+			case models.RuleActionDrop:
+				droppedBy[m.UserID] = true
+				state(m.UserID).Hidden = true
+			case models.RuleActionTag:
+				if s := state(m.UserID); !slices.Contains(s.Tags, m.Tag) {
+					s.Tags = append(s.Tags, m.Tag)
+				}
+			case models.RuleActionStar:
+				state(m.UserID).Starred = true
+			case models.RuleActionMarkRead:
+				state(m.UserID).Read = true
+			}
+		}
+
+		if subscribers > 0 && len(droppedBy) >= subscribers {
+			outcome.dropped++
+			continue
+		}
+
+		var articleStates []models.ArticleState
+		for _, s := range states {
+			articleStates = append(articleStates, *s)
+		}
+		outcome.kept = append(outcome.kept, article)
+		outcome.states = append(outcome.states, articleStates)
+	}
+
+	return outcome
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"RSSHub/internal/adapters/repo"
+	"RSSHub/internal/domain/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// tokenSize is the number of random bytes in API tokens
+const tokenSize = 32
+
+// AddUser creates a new user and returns its API token, only the hash of the token is stored.
+func (a *RssAggregator) AddUser(name string) (string, error) {
+	const op = "RssAggregator.AddUser"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	token, hash, err := newToken()
+	if err != nil {
+		log.Error("Failed to generate token", "error", err)
+		return "", errors.New("failed to generate token")
+	}
+
+	if _, err := a.userRepo.Create(ctx, name, hash); err != nil {
+		if errors.Is(err, repo.ErrUserExists) {
+			return "", models.ErrUserExists
+		}
+		log.Error("Failed to create user", "error", err)
+		return "", errors.New("failed to create user")
+	}
+
+	return token, nil
+}
+
+// ResetToken issues a new API token of the user, the previous one stops working.
+func (a *RssAggregator) ResetToken(name string) (string, error) {
+	const op = "RssAggregator.ResetToken"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	token, hash, err := newToken()
+	if err != nil {
+		log.Error("Failed to generate token", "error", err)
+		return "", errors.New("failed to generate token")
+	}
+
+	if err := a.userRepo.SetToken(ctx, name, hash); err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return "", models.ErrUserNotFound
+		}
+		log.Error("Failed to set token", "error", err)
+		return "", errors.New("failed to set token")
+	}
+
+	return token, nil
+}
+
+// SetAdmin grants or revokes admin rights of the user, admins manage fetching and config over the API.
+func (a *RssAggregator) SetAdmin(name string, admin bool) error {
+	const op = "RssAggregator.SetAdmin"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", name),
+		slog.Bool("admin", admin),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := a.userRepo.SetAdmin(ctx, name, admin); err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return models.ErrUserNotFound
+		}
+		log.Error("Failed to set admin rights", "error", err)
+		return errors.New("failed to set admin rights")
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the user with its subscriptions, feeds without other subscribers are deleted.
+func (a *RssAggregator) DeleteUser(name string) error {
+	const op = "RssAggregator.DeleteUser"
+	log := a.log.GetSlogLogger().With(
+		slog.String("op", op),
+		slog.String("user", name),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := a.userRepo.Delete(ctx, name); err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return models.ErrUserNotFound
+		}
+		log.Error("Failed to delete user", "error", err)
+		return errors.New("failed to delete user")
+	}
+
+	return nil
+}
+
+// ListUsers returns all users.
+func (a *RssAggregator) ListUsers() ([]*models.User, error) {
+	const op = "RssAggregator.ListUsers"
+	log := a.log.GetSlogLogger().With(slog.String("op", op))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	users, err := a.userRepo.List(ctx)
+	if err != nil {
+		log.Error("Failed to get user list", "error", err)
+		return nil, errors.New("failed to get user list")
+	}
+
+	return users, nil
+}
+
+// Authenticate returns the user owning the API token.
+func (a *RssAggregator) Authenticate(token string) (*models.User, error) {
+	const op = "RssAggregator.Authenticate"
+	log := a.log.GetSlogLogger().With(slog.String("op", op))
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, models.ErrInvalidToken
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	user, err := a.userRepo.GetByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return nil, models.ErrInvalidToken
+		}
+		log.Error("Failed to authenticate user", "error", err)
+		return nil, errors.New("failed to authenticate user")
+	}
+
+	return user, nil
+}
+
+// userID returns id of the user by name.
+func (a *RssAggregator) userID(ctx context.Context, name string) (string, error) {
+	user, err := a.userRepo.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return "", models.ErrUserNotFound
+		}
+		a.log.GetSlogLogger().Error("Failed to get user", "user", name, "error", err)
+		return "", errors.New("failed to get user")
+	}
+
+	return user.ID, nil
+}
+
+// adminID returns id of the user by name, users who are not admins are forbidden.
+func (a *RssAggregator) adminID(ctx context.Context, name string) (string, error) {
+	user, err := a.userRepo.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return "", models.ErrUserNotFound
+		}
+		a.log.GetSlogLogger().Error("Failed to get user", "user", name, "error", err)
+		return "", errors.New("failed to get user")
+	}
+
+	if !user.Admin {
+		return "", models.ErrForbidden
+	}
+	return user.ID, nil
+}
+
+// newToken returns a random API token with its hash
+func newToken() (string, string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
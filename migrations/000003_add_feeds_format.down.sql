@@ -0,0 +1 @@
+ALTER TABLE feeds DROP COLUMN IF EXISTS format;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE feeds
+    ADD COLUMN etag TEXT,
+    ADD COLUMN last_modified TEXT;
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS unparsed_pub_date;
\ No newline at end of file
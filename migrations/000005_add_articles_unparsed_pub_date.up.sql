@@ -0,0 +1 @@
+ALTER TABLE articles ADD COLUMN unparsed_pub_date TEXT;
@@ -0,0 +1,10 @@
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_feed_id_guid_key;
+
+-- Link was the only identity before, keeping the latest article for each link
+DELETE FROM articles a
+USING articles b
+WHERE a.link = b.link AND (a.created_at, a.id) < (b.created_at, b.id);
+
+ALTER TABLE articles ADD CONSTRAINT articles_link_key UNIQUE (link);
+ALTER TABLE articles DROP COLUMN IF EXISTS guid_backfilled;
+ALTER TABLE articles DROP COLUMN IF EXISTS guid;
@@ -0,0 +1,14 @@
+ALTER TABLE articles ADD COLUMN guid TEXT;
+-- Set on rows stored before guids were kept, cleared once the row is matched by a fetched item
+ALTER TABLE articles ADD COLUMN guid_backfilled BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- Same fallback as the fetcher uses for items without guid: sha256(link + '\n' + title).
+-- Guids of publishers were not stored, backfilled rows take the guid of the item with their link on the next fetch
+UPDATE articles
+SET guid = encode(sha256(convert_to(link || E'\n' || title, 'UTF8')), 'hex'),
+    guid_backfilled = TRUE;
+
+ALTER TABLE articles ALTER COLUMN guid SET NOT NULL;
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_link_key;
+ALTER TABLE articles ADD CONSTRAINT articles_feed_id_guid_key UNIQUE (feed_id, guid);
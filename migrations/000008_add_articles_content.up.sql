@@ -0,0 +1 @@
+ALTER TABLE articles ADD COLUMN content TEXT;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS categories;
+ALTER TABLE articles DROP COLUMN IF EXISTS author;
@@ -0,0 +1,14 @@
+ALTER TABLE articles ADD COLUMN author TEXT;
+
+CREATE TABLE categories(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE article_categories(
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+
+CREATE INDEX article_categories_category_id_idx ON article_categories (category_id);
@@ -0,0 +1 @@
+ALTER TABLE feeds DROP COLUMN IF EXISTS fetch_interval;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE feeds
+    DROP COLUMN IF EXISTS ttl,
+    DROP COLUMN IF EXISTS skip_hours,
+    DROP COLUMN IF EXISTS skip_days;
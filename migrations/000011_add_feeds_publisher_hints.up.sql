@@ -0,0 +1,4 @@
+ALTER TABLE feeds
+    ADD COLUMN ttl INTERVAL,
+    ADD COLUMN skip_hours INT[],
+    ADD COLUMN skip_days TEXT[];
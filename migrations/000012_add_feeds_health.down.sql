@@ -0,0 +1,5 @@
+ALTER TABLE feeds
+    DROP COLUMN IF EXISTS failure_count,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_fetch_at,
+    DROP COLUMN IF EXISTS disabled;
@@ -0,0 +1,5 @@
+ALTER TABLE feeds
+    ADD COLUMN failure_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN next_fetch_at TIMESTAMP,
+    ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
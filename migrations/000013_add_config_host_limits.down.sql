@@ -0,0 +1,3 @@
+ALTER TABLE config
+    DROP COLUMN IF EXISTS host_max_concurrency,
+    DROP COLUMN IF EXISTS host_min_spacing;
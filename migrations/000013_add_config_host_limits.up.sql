@@ -0,0 +1,3 @@
+ALTER TABLE config
+    ADD COLUMN host_max_concurrency INT NOT NULL DEFAULT 2,
+    ADD COLUMN host_min_spacing INTERVAL NOT NULL DEFAULT '1 second';
@@ -0,0 +1,17 @@
+CREATE TABLE fetch_jobs(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- A feed has at most one job waiting or in progress
+CREATE UNIQUE INDEX fetch_jobs_active_feed_idx ON fetch_jobs (feed_id) WHERE status IN ('pending', 'running');
+
+CREATE INDEX fetch_jobs_pending_run_at_idx ON fetch_jobs (run_at) WHERE status = 'pending';
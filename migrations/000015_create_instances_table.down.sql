@@ -0,0 +1,3 @@
+ALTER TABLE config ADD COLUMN run BOOL DEFAULT FALSE;
+
+DROP TABLE IF EXISTS instances;
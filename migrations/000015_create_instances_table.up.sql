@@ -0,0 +1,10 @@
+CREATE TABLE instances(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    host TEXT NOT NULL,
+    pid INT NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- The running aggregator is tracked by an advisory lock and its heartbeat row
+ALTER TABLE config DROP COLUMN IF EXISTS run;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS feeds_claimed_by_idx;
+
+ALTER TABLE feeds
+    DROP COLUMN IF EXISTS claimed_by,
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,5 @@
+ALTER TABLE feeds
+    ADD COLUMN claimed_by UUID REFERENCES instances (id) ON DELETE SET NULL,
+    ADD COLUMN lease_expires_at TIMESTAMP;
+
+CREATE INDEX feeds_claimed_by_idx ON feeds (claimed_by);
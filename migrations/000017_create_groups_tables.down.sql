@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS group_feeds;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,14 @@
+CREATE TABLE groups(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE group_feeds(
+    group_id UUID NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (group_id, feed_id)
+);
+
+CREATE INDEX group_feeds_feed_id_idx ON group_feeds (feed_id);
@@ -0,0 +1,8 @@
+-- Titles weigh more than descriptions in the rank of search results
+ALTER TABLE articles
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
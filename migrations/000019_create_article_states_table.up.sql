@@ -0,0 +1,9 @@
+-- Articles without a row are unread and not starred
+CREATE TABLE article_states(
+    article_id UUID PRIMARY KEY REFERENCES articles (id) ON DELETE CASCADE,
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    starred BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX article_states_starred_idx ON article_states (article_id) WHERE starred;
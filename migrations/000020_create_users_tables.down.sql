@@ -0,0 +1,16 @@
+-- Only the state of the default user is kept
+DELETE FROM groups WHERE user_id IS DISTINCT FROM (SELECT id FROM users WHERE name = 'default');
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_user_id_name_key;
+ALTER TABLE groups DROP COLUMN IF EXISTS user_id;
+ALTER TABLE groups ADD CONSTRAINT groups_name_key UNIQUE (name);
+
+DELETE FROM article_states WHERE user_id IS DISTINCT FROM (SELECT id FROM users WHERE name = 'default');
+DROP INDEX IF EXISTS article_states_article_id_idx;
+DROP INDEX IF EXISTS article_states_starred_idx;
+ALTER TABLE article_states DROP CONSTRAINT article_states_pkey;
+ALTER TABLE article_states DROP COLUMN IF EXISTS user_id;
+ALTER TABLE article_states ADD PRIMARY KEY (article_id);
+CREATE INDEX article_states_starred_idx ON article_states (article_id) WHERE starred;
+
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,42 @@
+CREATE TABLE users(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT UNIQUE NOT NULL,
+    token_hash TEXT UNIQUE, -- SHA-256 of the API token, NULL until a token is issued
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+-- Data created before users existed belongs to the default user
+INSERT INTO users(name) VALUES ('default');
+
+-- Feeds are fetched once for all users subscribed to them
+CREATE TABLE subscriptions(
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (user_id, feed_id)
+);
+
+CREATE INDEX subscriptions_feed_id_idx ON subscriptions (feed_id);
+
+INSERT INTO subscriptions(user_id, feed_id, created_at)
+SELECT u.id, f.id, f.created_at FROM users u, feeds f WHERE u.name = 'default';
+
+-- Article states are kept per user
+ALTER TABLE article_states ADD COLUMN user_id UUID REFERENCES users (id) ON DELETE CASCADE;
+UPDATE article_states SET user_id = (SELECT id FROM users WHERE name = 'default');
+ALTER TABLE article_states ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE article_states DROP CONSTRAINT article_states_pkey;
+ALTER TABLE article_states ADD PRIMARY KEY (user_id, article_id);
+
+DROP INDEX IF EXISTS article_states_starred_idx;
+CREATE INDEX article_states_starred_idx ON article_states (user_id, article_id) WHERE starred;
+CREATE INDEX article_states_article_id_idx ON article_states (article_id);
+
+-- Groups are kept per user
+ALTER TABLE groups ADD COLUMN user_id UUID REFERENCES users (id) ON DELETE CASCADE;
+UPDATE groups SET user_id = (SELECT id FROM users WHERE name = 'default');
+ALTER TABLE groups ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE groups DROP CONSTRAINT groups_name_key;
+ALTER TABLE groups ADD CONSTRAINT groups_user_id_name_key UNIQUE (user_id, name);
@@ -0,0 +1,3 @@
+ALTER TABLE article_states DROP COLUMN IF EXISTS hidden;
+
+DROP TABLE IF EXISTS rules;
@@ -0,0 +1,22 @@
+-- Rules of a user evaluated on fetched articles, scoped to a feed, a group of the user or all subscribed feeds
+CREATE TABLE rules(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds (id) ON DELETE CASCADE,
+    group_id UUID REFERENCES groups (id) ON DELETE CASCADE,
+    field TEXT NOT NULL CHECK (field IN ('title', 'description', 'author', 'category')),
+    match TEXT NOT NULL CHECK (match IN ('contains', 'regex')),
+    pattern TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('drop', 'tag', 'star', 'mark-read')),
+    tag TEXT, -- Category added by tag rules
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    CHECK (feed_id IS NULL OR group_id IS NULL),
+    CHECK ((action = 'tag') = (tag IS NOT NULL))
+);
+
+CREATE INDEX rules_user_id_idx ON rules (user_id);
+CREATE INDEX rules_feed_id_idx ON rules (feed_id);
+CREATE INDEX rules_group_id_idx ON rules (group_id);
+
+-- Articles dropped by rules of some of the subscribers are hidden from them
+ALTER TABLE article_states ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,34 @@
+-- Alerts of a user on newly stored articles, optionally scoped to a feed
+CREATE TABLE alerts(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds (id) ON DELETE CASCADE,
+    match TEXT NOT NULL CHECK (match IN ('keyword', 'regex')),
+    pattern TEXT NOT NULL,
+    webhook_url TEXT NOT NULL,
+    secret TEXT NOT NULL, -- Key of the HMAC signature of payloads
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX alerts_user_id_idx ON alerts (user_id);
+CREATE INDEX alerts_feed_id_idx ON alerts (feed_id);
+
+-- Webhook deliveries of articles matched by alerts, retried with backoff like fetch jobs
+CREATE TABLE alert_deliveries(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id UUID NOT NULL REFERENCES alerts (id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 6,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    response_status INT, -- HTTP status of the last attempt, NULL when no response was received
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (alert_id, article_id)
+);
+
+CREATE INDEX alert_deliveries_pending_run_at_idx ON alert_deliveries (run_at) WHERE status = 'pending';
+CREATE INDEX alert_deliveries_article_id_idx ON alert_deliveries (article_id);
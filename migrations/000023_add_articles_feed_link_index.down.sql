@@ -0,0 +1 @@
+DROP INDEX IF EXISTS articles_feed_id_link_idx;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS articles_feed_id_link_idx;
+-- Articles backfilled with the fallback guid are looked up by their link when the feed is fetched again
+CREATE INDEX articles_feed_id_link_idx ON articles (feed_id, link) WHERE guid_backfilled;
@@ -0,0 +1,5 @@
+-- Fails when several feeds have the same name, they must be renamed first
+ALTER TABLE feeds ADD CONSTRAINT feeds_name_key UNIQUE (name);
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_user_id_name_key;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS description, DROP COLUMN IF EXISTS name;
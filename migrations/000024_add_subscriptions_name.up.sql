@@ -0,0 +1,13 @@
+-- Feeds are named by their subscribers, the same feed may have another name for every user
+ALTER TABLE subscriptions ADD COLUMN name TEXT, ADD COLUMN description TEXT;
+
+UPDATE subscriptions s
+SET name = f.name, description = f.description
+FROM feeds f
+WHERE f.id = s.feed_id;
+
+ALTER TABLE subscriptions ALTER COLUMN name SET NOT NULL;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_user_id_name_key UNIQUE (user_id, name);
+
+-- Name of the feed is the one given by the user who added it, it is not shown to other users
+ALTER TABLE feeds DROP CONSTRAINT IF EXISTS feeds_name_key;
@@ -0,0 +1,5 @@
+-- Admins manage the whole deployment: fetching, config and status
+ALTER TABLE users ADD COLUMN admin BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- The default user owned everything before users existed
+UPDATE users SET admin = TRUE WHERE name = 'default';
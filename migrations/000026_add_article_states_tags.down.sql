@@ -0,0 +1 @@
+ALTER TABLE article_states DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,2 @@
+-- Tags of rules are kept per user, shared categories of articles come from the feeds only
+ALTER TABLE article_states ADD COLUMN tags TEXT[] DEFAULT '{}' NOT NULL;
@@ -0,0 +1,8 @@
+// Package migrations embeds SQL migrations of the database schema,
+// they are applied by the binary on start or with "rsshub migrate".
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
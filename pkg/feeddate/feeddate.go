@@ -0,0 +1,95 @@
+package feeddate
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrUnknownFormat = errors.New("feeddate: unknown date format")
+
+// layouts lists the date formats met in the wild, in the order they are tried.
+// RFC 822/1123 variants come from RSS, ISO 8601 variants from Atom, RDF (dc:date) and JSON Feed.
+var layouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339Nano,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"Mon, 2 Jan 06 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 MST",
+	"Mon, 2 January 2006 15:04:05 -0700",
+	"Mon, 2 January 2006 15:04:05 MST",
+	"Monday, 2 January 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 06 15:04 MST",
+	time.RFC850,
+	time.ANSIC,
+	time.UnixDate,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// zoneOffsets maps zone abbreviations allowed by RFC 822 (and a few common ones) to their UTC offsets in hours.
+// time.Parse keeps unknown abbreviations with a zero offset, so they are corrected after parsing.
+var zoneOffsets = map[string]int{
+	"UT":   0,
+	"UTC":  0,
+	"GMT":  0,
+	"Z":    0,
+	"EST":  -5,
+	"EDT":  -4,
+	"CST":  -6,
+	"CDT":  -5,
+	"MST":  -7,
+	"MDT":  -6,
+	"PST":  -8,
+	"PDT":  -7,
+	"CET":  1,
+	"CEST": 2,
+	"MSK":  3,
+	"JST":  9,
+}
+
+// Parse normalizes a feed date into UTC trying the common feed date layouts.
+func Parse(value string) (time.Time, error) {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return time.Time{}, ErrUnknownFormat
+	}
+
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		return fixZone(t).UTC(), nil
+	}
+
+	return time.Time{}, ErrUnknownFormat
+}
+
+// fixZone applies the real offset of a named zone that time.Parse did not know.
+func fixZone(t time.Time) time.Time {
+	name, offset := t.Zone()
+	hours, ok := zoneOffsets[strings.ToUpper(name)]
+	if !ok || offset == hours*3600 {
+		return t
+	}
+
+	loc := time.FixedZone(name, hours*3600)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,279 @@
+// Package migrator applies SQL migrations embedded in the binary.
+// Applied versions are tracked in the schema_migrations table in the format of golang-migrate,
+// so databases migrated with the migrate CLI are picked up as is.
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey identifies the advisory lock held while migrations are applied
+const lockKey int64 = 0x6d696772617465 // "migrate"
+
+var (
+	ErrDirty       = errors.New("migrator: database is dirty, fix the schema and force the version with the migrate CLI")
+	ErrNoMigration = errors.New("migrator: no migration to roll back")
+	ErrUnknown     = errors.New("migrator: database version is unknown to the binary")
+)
+
+// fileName matches migration files, e.g. 000001_create_config_table.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type (
+	// Migration is a pair of up and down scripts of one schema version
+	Migration struct {
+		Version int64
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	// Status is the state of the database schema
+	Status struct {
+		Version    int64 // Current version, 0 when nothing is applied
+		Dirty      bool  // A migration failed in the middle, set by the migrate CLI
+		Migrations []*Migration
+	}
+
+	Migrator struct {
+		pool       *pgxpool.Pool
+		migrations []*Migration // Sorted by version
+	}
+)
+
+// New reads migrations from the root of fsys
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrator: failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrator: invalid version of %s: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrator: failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations and returns them
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var applied []*Migration
+
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		version, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migrator: failed to apply %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the given number of the latest migrations and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var reverted []*Migration
+
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		version, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for range steps {
+			if version == 0 {
+				if len(reverted) == 0 {
+					return ErrNoMigration
+				}
+				return nil
+			}
+
+			i := slices.IndexFunc(m.migrations, func(migration *Migration) bool {
+				return migration.Version == version
+			})
+			if i < 0 {
+				return fmt.Errorf("%w: %d", ErrUnknown, version)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+
+			migration := m.migrations[i]
+			if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("migrator: failed to roll back %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+			version = previous
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status returns the current version of the schema with all known migrations
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrator: %w", err)
+	}
+	defer conn.Release()
+
+	if err := ensureTable(ctx, conn.Conn()); err != nil {
+		return nil, err
+	}
+
+	status := &Status{Migrations: m.migrations}
+	err = conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&status.Version, &status.Dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("migrator: failed to read version: %w", err)
+	}
+
+	return status, nil
+}
+
+// locked runs fn on a dedicated connection holding the migration advisory lock,
+// so concurrently started instances apply migrations one at a time.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	pooled, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("migrator: %w", err)
+	}
+	// The connection is closed instead of returned to the pool, which releases the lock in any case
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("migrator: failed to take lock: %w", err)
+	}
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	if err := fn(conn); err != nil {
+		return err
+	}
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+		return fmt.Errorf("migrator: failed to release lock: %w", err)
+	}
+
+	return nil
+}
+
+// version returns the applied version, a dirty database is refused
+func (m *Migrator) version(ctx context.Context, conn *pgx.Conn) (int64, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("migrator: failed to read version: %w", err)
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("%w (version %d)", ErrDirty, version)
+	}
+
+	return version, nil
+}
+
+// apply runs the script and stores the new version in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *pgx.Conn, script string, version int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Scripts are run with the simple protocol, so they may contain several statements
+	if _, err := tx.Exec(ctx, script, pgx.QueryExecModeSimpleProtocol); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+
+	if version > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations(version, dirty) VALUES ($1, FALSE)`, version); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ensureTable creates schema_migrations in the layout used by golang-migrate
+func ensureTable(ctx context.Context, conn *pgx.Conn) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("migrator: failed to create schema_migrations: %w", err)
+	}
+
+	return nil
+}
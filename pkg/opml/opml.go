@@ -0,0 +1,108 @@
+// Package opml reads and writes OPML 2.0 subscription lists.
+package opml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var ErrNotOPML = errors.New("opml: document is not OPML")
+
+type (
+	Document struct {
+		XMLName xml.Name `xml:"opml"`
+		Version string   `xml:"version,attr"`
+		Head    Head     `xml:"head"`
+		Body    Body     `xml:"body"`
+	}
+
+	Head struct {
+		Title       string `xml:"title,omitempty"`
+		DateCreated string `xml:"dateCreated,omitempty"` // RFC 822 date
+	}
+
+	Body struct {
+		Outlines []*Outline `xml:"outline"`
+	}
+
+	// Outline is a subscription when XMLURL is set, otherwise a folder of nested outlines
+	Outline struct {
+		Text        string     `xml:"text,attr"`
+		Title       string     `xml:"title,attr,omitempty"`
+		Type        string     `xml:"type,attr,omitempty"`
+		XMLURL      string     `xml:"xmlUrl,attr,omitempty"`
+		HTMLURL     string     `xml:"htmlUrl,attr,omitempty"`
+		Description string     `xml:"description,attr,omitempty"`
+		Outlines    []*Outline `xml:"outline"`
+	}
+
+	// Subscription is a feed outline with the names of the folders it is nested in, outermost first
+	Subscription struct {
+		*Outline
+		Folders []string
+	}
+)
+
+// Parse decodes an OPML document of any version
+func Parse(r io.Reader) (*Document, error) {
+	doc := new(Document)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		var unexpected xml.UnmarshalError
+		if errors.As(err, &unexpected) {
+			return nil, fmt.Errorf("%w: %s", ErrNotOPML, err)
+		}
+		return nil, fmt.Errorf("opml: failed to decode document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// Write encodes the document as indented XML with the XML declaration
+func (d *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("opml: failed to encode document: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Name returns the title of the outline, its text when the title is missing
+func (o *Outline) Name() string {
+	if title := strings.TrimSpace(o.Title); title != "" {
+		return title
+	}
+	return strings.TrimSpace(o.Text)
+}
+
+// Subscriptions returns all feed outlines of the document in document order
+func (d *Document) Subscriptions() []Subscription {
+	var subs []Subscription
+	walk(d.Body.Outlines, nil, &subs)
+	return subs
+}
+
+func walk(outlines []*Outline, folders []string, subs *[]Subscription) {
+	for _, o := range outlines {
+		if o.XMLURL != "" {
+			*subs = append(*subs, Subscription{Outline: o, Folders: folders})
+			continue
+		}
+
+		// Outlines without a name only group their children visually
+		nested := folders
+		if name := o.Name(); name != "" {
+			nested = append(folders[:len(folders):len(folders)], name)
+		}
+		walk(o.Outlines, nested, subs)
+	}
+}
@@ -17,6 +17,8 @@ type Config struct {
 
 	MaxOpenConns int32  `env:"POSTGRES_MAX_OPEN_CONN" default:"25"`
 	MaxIdleTime  string `env:"POSTGRES_MAX_IDLE_TIME" default:"15m"`
+
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" default:"true"` // Apply embedded migrations on start
 }
 
 func (c Config) DSN() string {
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// skippedElements are removed from the text together with their content.
+var skippedElements = []string{"script", "style", "head", "template"}
+
+// HTMLToText converts an HTML fragment into plain text:
+// tags are stripped, entities are decoded and whitespace is collapsed into single spaces.
+func HTMLToText(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			sb.WriteString(s)
+			break
+		}
+		sb.WriteString(s[:start])
+		s = s[start:]
+
+		// Comments may contain '>' so they are skipped up to their own terminator
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+len("-->"):]
+			continue
+		}
+
+		end := strings.IndexByte(s, '>')
+		if end < 0 || !isTagStart(s[1]) {
+			// Not a tag, e.g. "a < b"
+			sb.WriteByte('<')
+			s = s[1:]
+			continue
+		}
+
+		tag := s[1:end]
+		s = s[end+1:]
+
+		opening := !strings.HasPrefix(tag, "/") && !strings.HasSuffix(tag, "/")
+		if skipped := skippedElement(tagName(tag)); opening && skipped != "" {
+			closing := indexFold(s, "</"+skipped)
+			if closing < 0 {
+				break
+			}
+			s = s[closing:]
+			continue
+		}
+
+		// Tags are replaced with a space, so words of adjacent blocks are not glued together
+		sb.WriteByte(' ')
+	}
+
+	return strings.Join(strings.Fields(html.UnescapeString(sb.String())), " ")
+}
+
+// indexFold returns the index of the first instance of the lower-cased ASCII substr in s ignoring ASCII case, or -1.
+// The index is found in s itself, lower-casing s may change its length.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagName returns lower-cased element name of the tag body, e.g. `a href="..."` => "a".
+func tagName(tag string) string {
+	tag = strings.TrimPrefix(tag, "/")
+	if i := strings.IndexAny(tag, " \t\r\n/"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+func skippedElement(name string) string {
+	for _, e := range skippedElements {
+		if name == e {
+			return e
+		}
+	}
+	return ""
+}
+
+// Truncate shortens text to at most max runes, cutting at a word boundary and adding an ellipsis.
+func Truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := string(runes[:max])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "..."
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Hello, world", "Hello, world"},
+		{"tags and entities", "<p>Tom &amp; <b>Jerry</b></p>", "Tom & Jerry"},
+		{"not a tag", "a < b", "a < b"},
+		{"comment", "a<!-- <b> -->b", "ab"},
+		{"script", "a<script>var x = '<p>';</script>b", "a b"},
+		{"upper case closing", "a<STYLE>p {}</Style>b", "a b"},
+		{"unclosed script", "a<script>x", "a"},
+		{"non-ASCII", "<p>Привет, мир</p>", "Привет, мир"},
+		// Lower-casing "Ⱥ" takes more bytes, offsets of the lower-cased text don't fit the original
+		{"non-ASCII before script", "ȺȺȺȺ<script>ȺȺȺȺȺȺ</script>Ⱥ", "ȺȺȺȺ Ⱥ"},
+		{"non-ASCII in style", "<style>İİİİİİİİ</STYLE>ok", "ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToText(tt.in); got != tt.want {
+				t.Errorf("HTMLToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
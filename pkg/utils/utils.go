@@ -2,7 +2,9 @@ package utils
 
 import (
 	"RSSHub/internal/domain/models"
+	"RSSHub/pkg/migrator"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -10,45 +12,457 @@ import (
 func PrintHelp() {
 	text := `
   Usage:
-    rsshub COMMAND [OPTIONS]
+    rsshub [--user <name>] COMMAND [OPTIONS]
 
   Common Commands:
        add             add new RSS feed
        status          show current status of application
-       set-interval    set RSS fetch interval
+       set-interval    set RSS fetch interval (--feed-name <name> <duration|default> for one feed, admins only)
        set-workers     set number of workers
-       list            list available RSS feeds
-       delete          delete RSS feed
-       articles        show latest articles
+       set-host-limit  set max concurrent requests and min spacing per host: <count> <duration>
+       list            list subscribed RSS feeds (--group <name> lists feeds of the group, --num limits the list)
+       delete          unsubscribe from RSS feed, it is deleted when nobody else is subscribed
+       enable          enable RSS feed disabled after failed fetches (admins only)
+       jobs            show fetch jobs (--status pending|running|done|dead, --num limits the list)
+       search          full-text search of articles: "query" [--feed-name <name>] [--since <7d|12h>] [--num <num>]
+       articles        show latest articles of --feed-name <name> or --group <name> merged across its feeds
+                       (--category filters by topic, --with-media lists attached media files, --unread, --starred)
+       rules           manage rules applied to fetched articles: add, list, delete <id>, test <id> (dry run on stored articles)
+                       add --field <title|description|author|category> --match <contains|regex> --pattern <pattern>
+                           --action <drop|tag|star|mark-read> [--tag <tag>] [--feed-name <name>|--group <name>]
+       alerts          manage alerts posting new articles to webhooks: add, list, delete <id>, deliveries
+                       add --pattern <pattern> [--match <keyword|regex>] --webhook <url> [--feed-name <name>]
+                       deliveries [--status pending|running|delivered|failed] [--num <num>]
+       group           manage groups of feeds: create|delete <group>, add|remove <group> <feed name>, list
+       mark-read       mark articles of a feed as read: --feed-name <name> [--before <2006-01-02|2006-01-02 15:04:05>]
+       star            star an article by id: star <article id>
+       unstar          remove the star of an article: unstar <article id>
+       import          add feeds from an OPML file: --opml <file>, folders become groups
+       export          write subscribed feeds as OPML: --opml [file], standard output by default
+       user            manage users: add <name>, token <name> (issues a new API token), grant|revoke <name> (admin rights), delete <name>, list
+       migrate         manage database schema: up, down [steps], status
        fetch           starts the background process that periodically fetches and processes RSS feeds using a worker pool
+       serve           starts the HTTP API server (HTTP_ADDR, :8080 by default), fetching is controlled by POST /fetch/start|stop
+
+  Feeds, groups and article states belong to the user given by --user or RSSHUB_USER, "default" when neither is set.
+  HTTP requests authenticate with "Authorization: Bearer <token>", feed documents also accept ?token=<token>.
+  Alert webhooks receive JSON signed with the alert secret: "X-RSSHub-Signature: sha256=<hex HMAC-SHA256 of the body>".
 `
 	fmt.Println(text)
 }
 
-// PrintArticleList prints a formatted list of articles for a specific feed.
-func PrintArticleList(articles []*models.RSSItem, feedName string) {
+// summaryLength is the maximum length of an article summary in the list, in characters.
+const summaryLength = 200
+
+// PrintArticleList prints a formatted list of articles for the feed or group of the filter.
+// HTML of titles and summaries is converted to plain text.
+func PrintArticleList(articles []*models.RSSItem, filter models.ArticleFilter) {
 	format := `%d. [%s] %s
    %s
+`
+
+	fmt.Printf("# %s\n\n", articleSource(filter))
+	for i, article := range articles {
+		fmt.Printf(format, i+1, article.PublishedAt.Format(time.DateTime), HTMLToText(article.Title), articleLink(article, filter))
+		fmt.Printf("   ID: %s\n", articleID(article))
+		if byline := articleByline(article); byline != "" {
+			fmt.Printf("   %s\n", byline)
+		}
+
+		// Description is often a teaser, the full content is used when it is empty
+		summary := HTMLToText(article.Description)
+		if summary == "" {
+			summary = HTMLToText(article.Content)
+		}
+		if summary != "" {
+			fmt.Printf("   %s\n", Truncate(summary, summaryLength))
+		}
+		fmt.Println()
+	}
+}
+
+// articleSource returns the feed or group the articles are listed for, e.g. "Group: golang"
+func articleSource(filter models.ArticleFilter) string {
+	if filter.Group != "" {
+		return "Group: " + filter.Group
+	}
+	return "Feed: " + filter.FeedName
+}
+
+// articleLink returns link of the article, prefixed by its feed name when articles of feeds are merged
+func articleLink(article *models.RSSItem, filter models.ArticleFilter) string {
+	if filter.FeedName == "" {
+		return article.FeedName + " | " + article.Link
+	}
+	return article.Link
+}
+
+// articleID returns id of the article with its state, e.g. "0b7c...e1 (unread, starred)"
+func articleID(article *models.RSSItem) string {
+	state := "read"
+	if !article.Read {
+		state = "unread"
+	}
+	if article.Starred {
+		state += ", starred"
+	}
+	return fmt.Sprintf("%s (%s)", article.ID, state)
+}
+
+// articleByline returns author and categories of the article, e.g. "by Rob Pike | go, concurrency"
+func articleByline(article *models.RSSItem) string {
+	var parts []string
+	if article.Author != "" {
+		parts = append(parts, "by "+article.Author)
+	}
+	if len(article.Categories) != 0 {
+		parts = append(parts, strings.Join(article.Categories, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// PrintSearchResults prints articles found by the query with their highlighted snippets
+func PrintSearchResults(results []*models.SearchResult, query string) {
+	format := `%d. [%s] %s
+   %s | %s
+`
+
+	fmt.Printf("# Search: %s\n\n", query)
+	for i, result := range results {
+		article := result.Article
+		fmt.Printf(format, i+1, article.PublishedAt.Format(time.DateTime), HTMLToText(article.Title), article.FeedName, article.Link)
+		fmt.Printf("   ID: %s\n", articleID(article))
+		if snippet := HTMLToText(result.Snippet); snippet != "" {
+			fmt.Printf("   %s\n", snippet)
+		}
+		fmt.Println()
+	}
+}
+
+// ParseAge parses a duration like time.ParseDuration, whole days are also accepted, e.g. "7d"
+func ParseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
 
+// PrintMediaList prints articles of the feed or group of the filter with their media files, e.g. podcast episodes.
+func PrintMediaList(articles []*models.RSSItem, filter models.ArticleFilter) {
+	format := `%d. [%s] %s
+   %s
 `
 
-	fmt.Printf("# Feed: %s\n\n", feedName)
+	fmt.Printf("# %s (media)\n\n", articleSource(filter))
 	for i, article := range articles {
-		fmt.Printf(format, i+1, article.PubDate, article.Title, article.Link)
+		fmt.Printf(format, i+1, article.PublishedAt.Format(time.DateTime), article.Title, articleLink(article, filter))
+		fmt.Printf("   ID: %s\n", articleID(article))
+		for _, e := range article.Enclosures {
+			fmt.Printf("   - %s\n", formatEnclosure(e))
+		}
+		fmt.Println()
+	}
+}
+
+// formatEnclosure returns enclosure URL with its known attributes, e.g. "https://x/ep1.mp3 (audio/mpeg, 24.3 MB)"
+func formatEnclosure(e models.Enclosure) string {
+	var attrs []string
+	if e.Kind == models.EnclosureKindThumbnail {
+		attrs = append(attrs, "thumbnail")
+	}
+	if e.Type != "" {
+		attrs = append(attrs, e.Type)
+	}
+	if e.Length > 0 {
+		attrs = append(attrs, PrettySize(e.Length))
+	}
+
+	if len(attrs) == 0 {
+		return e.URL
+	}
+	return fmt.Sprintf("%s (%s)", e.URL, strings.Join(attrs, ", "))
+}
+
+// PrettySize returns size in bytes in human readable format, e.g. 1536 => "1.5 KB"
+func PrettySize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
 
 // PrintFeedsList prints a formatted list of available RSS feeds to the console.
-func PrintFeedsList(feeds []*models.Feed) {
+func PrintFeedsList(feeds []*models.Feed, group string) {
 	format := `%d. Name: %s
    URL: %s
+   Format: %s
+   Interval: %s
+   Health: %s
    Added: %s
 
 `
 
-	fmt.Print("# Available RSS Feeds\n\n")
+	if group != "" {
+		fmt.Printf("# RSS Feeds of group %s\n\n", group)
+	} else {
+		fmt.Print("# Available RSS Feeds\n\n")
+	}
 	for i, feed := range feeds {
-		fmt.Printf(format, i+1, feed.Name, feed.URL, feed.CreatedAt.Format(time.DateTime))
+		feedFormat := feed.Format
+		if feedFormat == "" {
+			feedFormat = "unknown"
+		}
+		interval := "default"
+		if feed.Interval != nil {
+			interval = PrettyDuration(*feed.Interval)
+		}
+		fmt.Printf(format, i+1, feed.Name, feed.URL, feedFormat, interval, FeedHealth(feed), feed.CreatedAt.Format(time.DateTime))
+	}
+}
+
+// PrintGroupsList prints a formatted list of groups with their feeds
+func PrintGroupsList(groups []*models.Group) {
+	format := `%d. Name: %s
+   Feeds: %s
+   Added: %s
+
+`
+
+	fmt.Print("# Groups\n\n")
+	for i, group := range groups {
+		feeds := strings.Join(group.FeedNames, ", ")
+		if feeds == "" {
+			feeds = "none"
+		}
+		fmt.Printf(format, i+1, group.Name, feeds, group.CreatedAt.Format(time.DateTime))
+	}
+}
+
+// PrintRulesList prints a formatted list of rules
+func PrintRulesList(rules []*models.Rule) {
+	format := `%d. ID: %s
+   Rule: %s
+   Added: %s
+
+`
+
+	fmt.Print("# Rules\n\n")
+	for i, rule := range rules {
+		fmt.Printf(format, i+1, rule.ID, PrettyRule(rule), rule.CreatedAt.Format(time.DateTime))
+	}
+}
+
+// PrintRuleTest prints stored articles matched by the rule
+func PrintRuleTest(rule *models.Rule, articles []*models.RSSItem) {
+	format := `%d. [%s] %s
+   %s | %s
+   ID: %s
+`
+
+	fmt.Printf("# Rule: %s\n", PrettyRule(rule))
+	fmt.Printf("# %d matched articles\n\n", len(articles))
+	for i, article := range articles {
+		fmt.Printf(format, i+1, article.PublishedAt.Format(time.DateTime), HTMLToText(article.Title), article.FeedName, article.Link, articleID(article))
+		if byline := articleByline(article); byline != "" {
+			fmt.Printf("   %s\n", byline)
+		}
+		fmt.Println()
+	}
+}
+
+// PrettyRule describes the rule, e.g. `tag "security" when title matches "cve-\d+" in group infosec`
+func PrettyRule(rule *models.Rule) string {
+	action := rule.Action
+	if rule.Action == models.RuleActionTag {
+		action = fmt.Sprintf("tag %q", rule.Tag)
+	}
+
+	match := "contains"
+	if rule.Match == models.RuleMatchRegex {
+		match = "matches"
+	}
+
+	scope := "in all feeds"
+	switch {
+	case rule.FeedName != "":
+		scope = "in feed " + rule.FeedName
+	case rule.Group != "":
+		scope = "in group " + rule.Group
+	}
+
+	return fmt.Sprintf("%s when %s %s %q %s", action, rule.Field, match, rule.Pattern, scope)
+}
+
+// PrintAlertsList prints a formatted list of alerts
+func PrintAlertsList(alerts []*models.Alert) {
+	format := `%d. ID: %s
+   Alert: %s
+   Webhook: %s
+   Added: %s
+
+`
+
+	fmt.Print("# Alerts\n\n")
+	for i, alert := range alerts {
+		fmt.Printf(format, i+1, alert.ID, PrettyAlert(alert), alert.WebhookURL, alert.CreatedAt.Format(time.DateTime))
+	}
+}
+
+// PrettyAlert describes the alert, e.g. `title or description contains "cve" in all feeds`
+func PrettyAlert(alert *models.Alert) string {
+	match := "contains"
+	if alert.Match == models.AlertMatchRegex {
+		match = "matches"
+	}
+
+	scope := "in all feeds"
+	if alert.FeedName != "" {
+		scope = "in feed " + alert.FeedName
+	}
+
+	return fmt.Sprintf("title or description %s %q %s", match, alert.Pattern, scope)
+}
+
+// PrintDeliveriesList prints a formatted list of alert deliveries
+func PrintDeliveriesList(deliveries []*models.AlertDelivery) {
+	format := `%d. ID: %s
+   Article: %s | %s
+   Alert: %s
+   Webhook: %s
+   Status: %s (attempt %d of %d)
+   Run at: %s
+   Updated: %s
+`
+
+	fmt.Print("# Alert Deliveries\n\n")
+	for i, d := range deliveries {
+		fmt.Printf(format, i+1, d.ID, HTMLToText(d.Article.Title), d.Article.FeedName, PrettyAlert(d.Alert), d.Alert.WebhookURL,
+			d.Status, d.Attempts, d.MaxAttempts, d.RunAt.Format(time.DateTime), d.UpdatedAt.Format(time.DateTime))
+		if d.ResponseStatus != 0 {
+			fmt.Printf("   Response status: %d\n", d.ResponseStatus)
+		}
+		if d.LastError != "" {
+			fmt.Printf("   Last error: %s\n", d.LastError)
+		}
+		fmt.Println()
+	}
+}
+
+// PrintUsersList prints a formatted list of users
+func PrintUsersList(users []*models.User) {
+	format := `%d. Name: %s
+   Feeds: %d
+   API token: %s
+   Admin: %t
+   Added: %s
+
+`
+
+	fmt.Print("# Users\n\n")
+	for i, user := range users {
+		token := "not issued"
+		if user.HasToken {
+			token = "issued"
+		}
+		fmt.Printf(format, i+1, user.Name, user.Feeds, token, user.Admin, user.CreatedAt.Format(time.DateTime))
+	}
+}
+
+// PrintJobsList prints a formatted list of fetch jobs
+func PrintJobsList(jobs []*models.FetchJob) {
+	format := `%d. Feed: %s
+   Status: %s (attempt %d of %d)
+   Run at: %s
+   Updated: %s
+`
+
+	fmt.Print("# Fetch Jobs\n\n")
+	for i, job := range jobs {
+		fmt.Printf(format, i+1, job.FeedName, job.Status, job.Attempts, job.MaxAttempts, job.RunAt.Format(time.DateTime), job.UpdatedAt.Format(time.DateTime))
+		if job.LastError != "" {
+			fmt.Printf("   Last error: %s\n", job.LastError)
+		}
+		fmt.Println()
+	}
+}
+
+// PrintImportResults prints the outcome of every imported subscription with the totals
+func PrintImportResults(results []*models.ImportResult) {
+	counts := make(map[string]int)
+
+	fmt.Print("# Imported Feeds\n\n")
+	for i, result := range results {
+		counts[result.Status]++
+
+		fmt.Printf("%d. [%s] %s\n   URL: %s\n", i+1, result.Status, result.Name, result.URL)
+		if result.Group != "" {
+			fmt.Printf("   Group: %s\n", result.Group)
+		}
+		if result.Reason != "" {
+			fmt.Printf("   Reason: %s\n", result.Reason)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Total: %d, added: %d, skipped: %d, failed: %d\n",
+		len(results), counts[models.ImportAdded], counts[models.ImportSkipped], counts[models.ImportFailed])
+}
+
+// PrintMigrations prints migrations applied or rolled back by the migrate command
+func PrintMigrations(title string, migrations []*migrator.Migration) {
+	if len(migrations) == 0 {
+		fmt.Println("Schema is up to date, nothing to do")
+		return
+	}
+
+	fmt.Printf("# %s\n\n", title)
+	for _, m := range migrations {
+		fmt.Printf("   %06d %s\n", m.Version, m.Name)
+	}
+}
+
+// PrintMigrationStatus prints the current schema version and the state of every known migration
+func PrintMigrationStatus(status *migrator.Status) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+
+	fmt.Printf("# Schema version: %d%s\n\n", status.Version, dirty)
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Version <= status.Version {
+			state = "applied"
+		}
+		fmt.Printf("   %06d %-40s %s\n", m.Version, m.Name, state)
+	}
+}
+
+// FeedHealth returns fetch health of the feed, e.g. "failing (3 in a row), next try at 2025-01-02 15:04:05: <error>"
+func FeedHealth(feed *models.Feed) string {
+	switch {
+	case feed.Disabled:
+		return fmt.Sprintf("disabled after %d failures: %s", feed.FailureCount, feed.LastError)
+	case feed.FailureCount > 0:
+		health := fmt.Sprintf("failing (%d in a row)", feed.FailureCount)
+		if feed.NextFetchAt != nil {
+			health += ", next try at " + feed.NextFetchAt.Format(time.DateTime)
+		}
+		return health + ": " + feed.LastError
+	default:
+		return "ok"
 	}
 }
 
@@ -99,14 +513,28 @@ func PrettyRssConfig(c *models.RssConfig) string {
 	var sb strings.Builder
 
 	sb.WriteString("Current configuration:\n")
-	status := "stopped"
-	if c.Run {
-		status = "running"
-	}
-
-	sb.WriteString(fmt.Sprintf("  Status:         %v\n", status))
 	sb.WriteString(fmt.Sprintf("  Worker count:   %d\n", c.WorkerCount))
-	sb.WriteString(fmt.Sprintf("  Timer interval: %s", PrettyDuration(c.TimerInterval)))
+	sb.WriteString(fmt.Sprintf("  Timer interval: %s\n", PrettyDuration(c.TimerInterval)))
+	sb.WriteString(fmt.Sprintf("  Host limits:    %d concurrent, %s spacing", c.HostMaxConcurrency, c.HostMinSpacing))
+
+	return sb.String()
+}
+
+// PrettyStatus returns configuration and scheduling state of the aggregator
+func PrettyStatus(s *models.AggregatorStatus) string {
+	var sb strings.Builder
+
+	sb.WriteString(PrettyRssConfig(s.Config))
+	if len(s.Instances) == 0 {
+		sb.WriteString("\n  Status:         stopped")
+	}
+	for _, instance := range s.Instances {
+		sb.WriteString(fmt.Sprintf("\n  Status:         running on %s (pid %d) since %s, last seen %s, %d leased feeds",
+			instance.Host, instance.PID, instance.StartedAt.Format(time.DateTime), instance.LastSeen.Format(time.DateTime), instance.Leases))
+	}
+	sb.WriteString(fmt.Sprintf("\n  Held back:      %d feeds (by publisher ttl/skipHours/skipDays)", s.HeldBackFeeds))
+	sb.WriteString(fmt.Sprintf("\n  Fetch jobs:     %d pending, %d running, %d done, %d dead",
+		s.Jobs[models.JobPending], s.Jobs[models.JobRunning], s.Jobs[models.JobDone], s.Jobs[models.JobDead]))
 
 	return sb.String()
 }